@@ -23,7 +23,7 @@ import (
 func AssertEq(expected, actual interface{}, errorParts ...interface{}) {
 	assertThat(
 		actual,
-		oglematchers.Equals(expected),
+		equalsValue(expected),
 		1,
 		errorParts)
 }
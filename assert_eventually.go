@@ -0,0 +1,57 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"time"
+
+	"github.com/jacobsa/oglematchers"
+)
+
+// AssertEventually is identical to ExpectEventually, except that in the event
+// of failure it halts the currently running test immediately.
+func AssertEventually(
+	f func() interface{},
+	m oglematchers.Matcher,
+	timeout time.Duration,
+	interval time.Duration,
+	errorParts ...interface{}) {
+	if !expectEventually(f, m, timeout, interval, 1, errorParts) {
+		AbortTest()
+	}
+}
+
+// AssertConsistently is identical to ExpectConsistently, except that in the
+// event of failure it halts the currently running test immediately.
+func AssertConsistently(
+	f func() interface{},
+	m oglematchers.Matcher,
+	duration time.Duration,
+	interval time.Duration,
+	errorParts ...interface{}) {
+	passed := expectConsistently(
+		f,
+		m,
+		duration,
+		interval,
+		FailureAssertion,
+		1,
+		errorParts)
+
+	if !passed {
+		AbortTest()
+	}
+}
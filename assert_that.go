@@ -24,7 +24,7 @@ func assertThat(
 	m oglematchers.Matcher,
 	depth int,
 	errorParts []interface{}) {
-	passed := expectThat(x, m, depth+1, errorParts)
+	passed := checkThat(x, m, FailureAssertion, depth+1, errorParts)
 	if !passed {
 		AbortTest()
 	}
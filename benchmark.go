@@ -0,0 +1,138 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"testing"
+)
+
+// RunBenchmarks runs the benchmark methods of the registered test suites as
+// sub-benchmarks of the supplied benchmark. This is the bridge between
+// ogletest benchmarks and `go test -bench`, in the same way that RunTests is
+// for tests. For example:
+//
+//     func BenchmarkOgletest(b *testing.B) { ogletest.RunBenchmarks(b) }
+//
+// Each benchmark is named after its suite and method, minus the "Benchmark"
+// prefix, so FooTest.BenchmarkParse is run as
+// "BenchmarkOgletest/FooTest/Parse" and can be selected with -bench in the
+// usual way. See RegisterTestSuite for how benchmark methods are declared.
+//
+// The suite's SetUpTestSuite and TearDownTestSuite methods run once around the
+// benchmarks that -bench selects, and not at all if it selects none of them.
+// SetUp and TearDown run around each call to the benchmark method (the testing
+// package calls it several times with increasing b.N), even if it calls
+// b.Fatal, and are excluded from the timing. Failures reported with ExpectThat
+// and friends are reported to the benchmark with b.Error.
+func RunBenchmarks(b *testing.B) {
+	runBenchmarks(b, registeredSuites)
+}
+
+// runBenchmarks does the real work of RunBenchmarks.
+func runBenchmarks(b *testing.B, suites []TestSuite) {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	for _, suite := range suites {
+		if len(suite.Benchmarks) == 0 {
+			continue
+		}
+
+		suite := suite
+		b.Run(suite.Name, func(b *testing.B) {
+			// Set up the suite only once a benchmark that -bench selects runs, so
+			// that suites whose benchmarks are all filtered out are skipped.
+			setUp := false
+			for _, bf := range suite.Benchmarks {
+				bf := bf
+				b.Run(bf.Name, func(b *testing.B) {
+					if !setUp && suite.SetUp != nil {
+						suite.SetUp()
+					}
+
+					setUp = true
+					runBenchmarkFunction(b, bf)
+				})
+			}
+
+			if setUp && suite.TearDown != nil {
+				suite.TearDown()
+			}
+		})
+	}
+}
+
+// Run a single call of the supplied benchmark function, with its SetUp and
+// TearDown, reporting any failures to b.
+func runBenchmarkFunction(b *testing.B, bf BenchmarkFunction) {
+	// Time only the benchmark body.
+	b.StopTimer()
+	b.ResetTimer()
+
+	body := func() {
+		b.StartTimer()
+		defer b.StopTimer()
+		bf.Run(b)
+	}
+
+	runIsolated(bf.SetUp, body, bf.TearDown, func(failures []FailureRecord) {
+		for _, r := range failures {
+			b.Errorf("%s:%d:\n%s", r.FileName, r.LineNumber, r.Error)
+		}
+	})
+}
+
+// Run the supplied body, preceded by setUp and followed by tearDown if they are
+// non-nil, as a test of its own outside of RunTests (e.g. for a benchmark or
+// fuzz input), passing the failures reported to report. These include any
+// reported by goroutines that outlive the call.
+//
+// tearDown and report run even if the body exits the goroutine with
+// runtime.Goexit, as b.Fatal and t.FailNow do.
+func runIsolated(
+	setUp func(*TestInfo),
+	body func(),
+	tearDown func(),
+	report func(failures []FailureRecord)) {
+	// Set up a clean slate, as runTestFunction does for tests. The testing
+	// package calls each benchmark or fuzz function on a new goroutine.
+	ti := newTestInfo()
+	setCurrentlyRunningTest(ti)
+	defer ti.cancel()
+
+	defer func() {
+		ti.goroutines.Wait()
+
+		if tearDown != nil {
+			runWithProtection(tearDown)
+		}
+
+		ti.MockController.Finish()
+
+		setCurrentlyRunningTest(nil)
+		failures := ti.finish()
+		report(append(failures, takeLateFailureRecords()...))
+	}()
+
+	setUpPanicked := false
+	if setUp != nil {
+		setUpPanicked = runWithProtection(func() { setUp(ti) })
+	}
+
+	if !setUpPanicked {
+		runWithProtection(body)
+	}
+}
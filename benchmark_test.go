@@ -0,0 +1,132 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// Events recorded by benchmarkSuite.
+var benchmarkEvents []string
+
+type benchmarkSuite struct {
+	setUp bool
+}
+
+func (t *benchmarkSuite) SetUpTestSuite() {
+	benchmarkEvents = append(benchmarkEvents, "SetUpTestSuite")
+}
+
+func (t *benchmarkSuite) TearDownTestSuite() {
+	benchmarkEvents = append(benchmarkEvents, "TearDownTestSuite")
+}
+
+func (t *benchmarkSuite) SetUp(ti *TestInfo) {
+	t.setUp = true
+}
+
+func (t *benchmarkSuite) SomeTest() {}
+
+func (t *benchmarkSuite) BenchmarkLoop(b *testing.B) {
+	benchmarkEvents = append(benchmarkEvents, fmt.Sprintf("Loop %v", t.setUp))
+	for i := 0; i < b.N; i++ {
+	}
+}
+
+type fatalBenchmarkSuite struct{}
+
+func (t *fatalBenchmarkSuite) TearDown() {
+	benchmarkEvents = append(benchmarkEvents, "TearDown")
+}
+
+func (t *fatalBenchmarkSuite) BenchmarkFatal(b *testing.B) {
+	benchmarkEvents = append(benchmarkEvents, "Fatal")
+	b.Fatal("taco")
+}
+
+type misnamedBenchmarkSuite struct{}
+
+func (t *misnamedBenchmarkSuite) Loop(b *testing.B) {}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestBenchmarkMethods(t *testing.T) {
+	suite := makeTestSuite("RegisterTestSuite", &benchmarkSuite{})
+
+	if len(suite.TestFunctions) != 1 {
+		t.Fatalf("Expected one test function, got %d", len(suite.TestFunctions))
+	}
+
+	if len(suite.Benchmarks) != 1 {
+		t.Fatalf("Expected one benchmark, got %d", len(suite.Benchmarks))
+	}
+
+	expectEqStr(t, "Loop", suite.Benchmarks[0].Name)
+}
+
+func TestMisnamedBenchmarkMethod(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &misnamedBenchmarkSuite{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in misnamedBenchmarkSuite:\n"+
+			"\tLoop takes a *testing.B, but its name doesn't start with Benchmark",
+		msg)
+}
+
+func TestRunBenchmarks(t *testing.T) {
+	benchmarkEvents = nil
+	suite := makeTestSuite("RegisterTestSuite", &benchmarkSuite{})
+
+	testing.Benchmark(func(b *testing.B) {
+		runBenchmarks(b, []TestSuite{suite})
+	})
+
+	// The testing package calls the benchmark several times, each with SetUp
+	// run first.
+	if len(benchmarkEvents) < 3 {
+		t.Fatalf("Too few events: %v", benchmarkEvents)
+	}
+
+	expectEqStr(t, "SetUpTestSuite", benchmarkEvents[0])
+	for _, e := range benchmarkEvents[1 : len(benchmarkEvents)-1] {
+		expectEqStr(t, "Loop true", e)
+	}
+
+	expectEqStr(t, "TearDownTestSuite", benchmarkEvents[len(benchmarkEvents)-1])
+}
+
+func TestBenchmarkFatalRunsTearDown(t *testing.T) {
+	benchmarkEvents = nil
+	suite := makeTestSuite("RegisterTestSuite", &fatalBenchmarkSuite{})
+
+	testing.Benchmark(func(b *testing.B) {
+		runBenchmarks(b, []TestSuite{suite})
+	})
+
+	assertEqInt(t, 2, len(benchmarkEvents))
+	expectEqStr(t, "Fatal", benchmarkEvents[0])
+	expectEqStr(t, "TearDown", benchmarkEvents[1])
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+var fColor = flag.String(
+	"ogletest.color",
+	"auto",
+	"Whether to color output: auto, always, or never. In auto mode, output is "+
+		"colored if stdout is a terminal and NO_COLOR is unset or empty.")
+
+// ANSI escape sequences for the colors used in output.
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorBold  = "\x1b[1m"
+	colorReset = "\x1b[0m"
+)
+
+// Whether output should be colored, as decided by RunTests according to the
+// user-supplied flag and the environment.
+var gUseColor bool
+
+// Decide whether output should be colored, panicking if the flag is invalid.
+func shouldUseColor() bool {
+	switch *fColor {
+	case "always":
+		return true
+
+	case "never":
+		return false
+
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false
+		}
+
+		fi, err := os.Stdout.Stat()
+		if err != nil {
+			return false
+		}
+
+		return fi.Mode()&os.ModeCharDevice != 0
+
+	default:
+		panic("Invalid value for --ogletest.color: " + *fColor)
+	}
+}
+
+// Wrap s in the supplied color, if output is being colored.
+func colorize(color string, s string) string {
+	if !gUseColor {
+		return s
+	}
+
+	return color + s + colorReset
+}
+
+// Color the lines of a failure record's error that deserve attention: the
+// Expected and Actual lines, and panic headers.
+func colorizeError(s string) string {
+	if !gUseColor {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "Expected: "):
+			lines[i] = colorize(colorGreen, line)
+
+		case strings.HasPrefix(line, "Actual:   "):
+			lines[i] = colorize(colorRed, line)
+
+		case strings.HasPrefix(line, "panic: "):
+			lines[i] = colorize(colorBold+colorRed, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"os"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestColorizeErrorDisabled(t *testing.T) {
+	gUseColor = false
+
+	s := "Expected: 17\nActual:   19"
+	expectEqStr(t, s, colorizeError(s))
+}
+
+func TestColorizeErrorEnabled(t *testing.T) {
+	gUseColor = true
+	defer func() { gUseColor = false }()
+
+	expectEqStr(
+		t,
+		"\x1b[32mExpected: 17\x1b[0m\n\x1b[31mActual:   19\x1b[0m\ntaco",
+		colorizeError("Expected: 17\nActual:   19\ntaco"))
+
+	expectEqStr(
+		t,
+		"\x1b[1m\x1b[31mpanic: taco\x1b[0m\n\nfoo.Bar",
+		colorizeError("panic: taco\n\nfoo.Bar"))
+}
+
+func TestShouldUseColor(t *testing.T) {
+	defer func(old string) { *fColor = old }(*fColor)
+
+	*fColor = "always"
+	if !shouldUseColor() {
+		t.Errorf("Expected color for --ogletest.color=always")
+	}
+
+	*fColor = "never"
+	if shouldUseColor() {
+		t.Errorf("Expected no color for --ogletest.color=never")
+	}
+
+	*fColor = "auto"
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	if shouldUseColor() {
+		t.Errorf("Expected no color with NO_COLOR set")
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/jacobsa/oglematchers"
+)
+
+var fDiffContext = flag.Int(
+	"ogletest.diff_context",
+	3,
+	"Number of unchanged lines to show around each change in failure diffs.")
+
+var fDiffMaxLines = flag.Int(
+	"ogletest.diff_max_lines",
+	100,
+	"Maximum number of lines of diff to print per failure, or zero for no limit.")
+
+// Diffs are only computed for inputs with at most this many lines on each
+// side, to bound the cost of the quadratic algorithm used.
+const maxDiffInputLines = 2000
+
+////////////////////////////////////////////////////////////////////////
+// Finding expected values
+////////////////////////////////////////////////////////////////////////
+
+// A matcher for equality with a known value, used by ExpectEq and AssertEq so
+// that failures can be diffed against the expected value.
+type equalsValueMatcher struct {
+	oglematchers.Matcher
+	expected interface{}
+}
+
+func equalsValue(x interface{}) oglematchers.Matcher {
+	return &equalsValueMatcher{oglematchers.Equals(x), x}
+}
+
+var equalsMatcherType = reflect.TypeOf(oglematchers.Equals(0))
+var deepEqualsMatcherType = reflect.TypeOf(oglematchers.DeepEquals(0))
+
+// If m is an equality matcher (that used by ExpectEq, Equals, or DeepEquals),
+// return the value that it expects.
+//
+// Equals and DeepEquals don't expose their expected values, so these are read
+// from their unexported fields, each checked by name and type first. If a
+// version of oglematchers stores them differently, there is no expected value.
+func expectedValue(m oglematchers.Matcher) (x interface{}, ok bool) {
+	switch reflect.TypeOf(m) {
+	case reflect.TypeOf(&equalsValueMatcher{}):
+		x = m.(*equalsValueMatcher).expected
+		ok = true
+
+	case deepEqualsMatcherType:
+		var f reflect.Value
+		if f, ok = matcherField(m, "x", emptyInterfaceType); ok {
+			x = f.Interface()
+		}
+
+	case equalsMatcherType:
+		var f reflect.Value
+		if f, ok = matcherField(m, "expectedValue", reflectValueType); ok {
+			if v := f.Interface().(reflect.Value); v.IsValid() && v.CanInterface() {
+				x = v.Interface()
+			}
+		}
+	}
+
+	return
+}
+
+var emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+var reflectValueType = reflect.TypeOf(reflect.Value{})
+
+// Return the named field of the struct to which m points, readable even if it
+// is unexported, if there is such a field of the given type.
+func matcherField(
+	m oglematchers.Matcher,
+	name string,
+	typ reflect.Type) (f reflect.Value, ok bool) {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	sf, found := v.Elem().Type().FieldByName(name)
+	if !found || len(sf.Index) != 1 || sf.Type != typ {
+		return
+	}
+
+	f = unexportedField(v.Elem().Field(sf.Index[0]))
+	ok = true
+	return
+}
+
+// Return a copy of the supplied addressable struct field that may be used as
+// an exported one, e.g. with Interface.
+func unexportedField(f reflect.Value) reflect.Value {
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// Return a line-oriented diff between the expected value for m and the
+// supplied actual value, or the empty string if a diff wouldn't be useful.
+func diffForMatcher(m oglematchers.Matcher, actual interface{}) string {
+	x, ok := expectedValue(m)
+	if !ok {
+		return ""
+	}
+
+	// Equals compares most values by identity or numerically, so only its
+	// strings are worth diffing.
+	e := reflect.ValueOf(x)
+	if reflect.TypeOf(m) == equalsMatcherType && e.Kind() != reflect.String {
+		return ""
+	}
+
+	a := reflect.ValueOf(actual)
+	if !e.IsValid() || !a.IsValid() || e.Type() != a.Type() {
+		return ""
+	}
+
+	return diffLines(formatLines(e), formatLines(a), *fDiffContext, *fDiffMaxLines)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Formatting values
+////////////////////////////////////////////////////////////////////////
+
+// Format the supplied value as a sequence of lines, with one line per struct
+// field, slice element, or map entry. Strings are split on newlines.
+func formatLines(v reflect.Value) []string {
+	if v.Kind() == reflect.String {
+		return strings.Split(v.String(), "\n")
+	}
+
+	buf := new(bytes.Buffer)
+	writeValue(buf, v, "", make(map[uintptr]bool))
+	return strings.Split(buf.String(), "\n")
+}
+
+// Write a multi-line representation of v to buf, using indent as the prefix
+// for lines after the first. visited contains the pointers currently being
+// formatted, to guard against cycles.
+func writeValue(
+	buf *bytes.Buffer,
+	v reflect.Value,
+	indent string,
+	visited map[uintptr]bool) {
+	if !v.IsValid() {
+		buf.WriteString("nil")
+		return
+	}
+
+	inner := indent + "  "
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+
+		if visited[v.Pointer()] {
+			fmt.Fprintf(buf, "<cycle to %v>", v.Type())
+			return
+		}
+
+		visited[v.Pointer()] = true
+		defer delete(visited, v.Pointer())
+
+		buf.WriteString("&")
+		writeValue(buf, v.Elem(), indent, visited)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+
+		writeValue(buf, v.Elem(), indent, visited)
+
+	case reflect.Struct:
+		if v.NumField() == 0 {
+			fmt.Fprintf(buf, "%v{}", v.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "%v{\n", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			fmt.Fprintf(buf, "%s%s: ", inner, v.Type().Field(i).Name)
+			writeValue(buf, v.Field(i), inner, visited)
+			buf.WriteString(",\n")
+		}
+
+		fmt.Fprintf(buf, "%s}", indent)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+
+		if v.Len() == 0 {
+			fmt.Fprintf(buf, "%v{}", v.Type())
+			return
+		}
+
+		fmt.Fprintf(buf, "%v{\n", v.Type())
+		for i := 0; i < v.Len(); i++ {
+			buf.WriteString(inner)
+			writeValue(buf, v.Index(i), inner, visited)
+			buf.WriteString(",\n")
+		}
+
+		fmt.Fprintf(buf, "%s}", indent)
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+
+		if v.Len() == 0 {
+			fmt.Fprintf(buf, "%v{}", v.Type())
+			return
+		}
+
+		// Sort the keys by their formatted representations, so that the output
+		// is stable.
+		type entry struct {
+			key       reflect.Value
+			formatted string
+		}
+
+		var entries []entry
+		for _, k := range v.MapKeys() {
+			kBuf := new(bytes.Buffer)
+			writeValue(kBuf, k, inner, visited)
+			entries = append(entries, entry{k, kBuf.String()})
+		}
+
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].formatted < entries[j].formatted
+		})
+
+		fmt.Fprintf(buf, "%v{\n", v.Type())
+		for _, e := range entries {
+			fmt.Fprintf(buf, "%s%s: ", inner, e.formatted)
+			writeValue(buf, v.MapIndex(e.key), inner, visited)
+			buf.WriteString(",\n")
+		}
+
+		fmt.Fprintf(buf, "%s}", indent)
+
+	case reflect.String:
+		buf.WriteString(strconv.Quote(v.String()))
+
+	default:
+		fmt.Fprintf(buf, "%v", v)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Diffing lines
+////////////////////////////////////////////////////////////////////////
+
+// Return a diff between the supplied sequences of lines, showing context
+// unchanged lines around each change and at most maxLines lines in total
+// (unless maxLines is zero). Return the empty string if the inputs are equal,
+// if both consist of a single line (in which case the usual Expected/Actual
+// output says it all), or if they are too large to diff.
+func diffLines(e, a []string, context int, maxLines int) string {
+	if len(e) <= 1 && len(a) <= 1 {
+		return ""
+	}
+
+	if len(e) > maxDiffInputLines || len(a) > maxDiffInputLines {
+		return ""
+	}
+
+	// Compute the lengths of the longest common subsequences of each pair of
+	// suffixes.
+	lcs := make([][]int, len(e)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(a)+1)
+	}
+
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := len(a) - 1; j >= 0; j-- {
+			switch {
+			case e[i] == a[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Walk the table to produce an edit script, with each line prefixed by
+	// "-" (only in expected), "+" (only in actual), or " " (in both).
+	var edits []string
+	i, j := 0, 0
+	for i < len(e) || j < len(a) {
+		switch {
+		case i < len(e) && j < len(a) && e[i] == a[j]:
+			edits = append(edits, "  "+e[i])
+			i++
+			j++
+		case j == len(a) || (i < len(e) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, "- "+e[i])
+			i++
+		default:
+			edits = append(edits, "+ "+a[j])
+			j++
+		}
+	}
+
+	// Decide which lines to show: the changes, plus context lines on either
+	// side of each.
+	show := make([]bool, len(edits))
+	changed := false
+	for k, edit := range edits {
+		if edit[0] == ' ' {
+			continue
+		}
+
+		changed = true
+		for l := k - context; l <= k+context; l++ {
+			if l >= 0 && l < len(edits) {
+				show[l] = true
+			}
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+
+	// Print the lines, eliding runs of unchanged lines and truncating if
+	// necessary.
+	buf := new(bytes.Buffer)
+	buf.WriteString("Diff (-expected +actual):")
+
+	printed := 0
+	for k := 0; k < len(edits); k++ {
+		if !show[k] {
+			if k == 0 || show[k-1] {
+				buf.WriteString("\n  ...")
+			}
+
+			continue
+		}
+
+		if maxLines > 0 && printed == maxLines {
+			remaining := 0
+			for _, s := range show[k:] {
+				if s {
+					remaining++
+				}
+			}
+
+			fmt.Fprintf(buf, "\n  ... (%d more lines)", remaining)
+			break
+		}
+
+		buf.WriteString("\n")
+		buf.WriteString(edits[k])
+		printed++
+	}
+
+	return buf.String()
+}
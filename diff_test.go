@@ -0,0 +1,143 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacobsa/oglematchers"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestDiffLinesIdentical(t *testing.T) {
+	lines := []string{"a", "b", "c"}
+	expectEqStr(t, "", diffLines(lines, lines, 3, 0))
+}
+
+func TestDiffLinesSingleLines(t *testing.T) {
+	expectEqStr(t, "", diffLines([]string{"a"}, []string{"b"}, 3, 0))
+}
+
+func TestDiffLinesContext(t *testing.T) {
+	e := strings.Split("a b c d e f g h i j", " ")
+	a := strings.Split("a b c d E f g h i j", " ")
+
+	expectEqStr(
+		t,
+		"Diff (-expected +actual):\n  ...\n  d\n- e\n+ E\n  f\n  ...",
+		diffLines(e, a, 1, 0))
+}
+
+func TestDiffLinesTruncation(t *testing.T) {
+	e := strings.Split("a b c d e", " ")
+	a := strings.Split("v w x y z", " ")
+
+	expectEqStr(
+		t,
+		"Diff (-expected +actual):\n- a\n- b\n- c\n  ... (7 more lines)",
+		diffLines(e, a, 0, 3))
+}
+
+func TestDiffForEqualsStrings(t *testing.T) {
+	expectEqStr(
+		t,
+		"Diff (-expected +actual):\n  a\n- b\n+ c",
+		diffForMatcher(oglematchers.Equals("a\nb"), "a\nc"))
+}
+
+func TestNoDiffForEqualsNonStrings(t *testing.T) {
+	expectEqStr(t, "", diffForMatcher(oglematchers.Equals(17), "a\nb"))
+
+	p := &[]int{1, 2}
+	q := &[]int{1, 3}
+	expectEqStr(t, "", diffForMatcher(oglematchers.Equals(p), q))
+}
+
+// A matcher laid out differently from the oglematchers ones.
+type otherLayoutMatcher struct {
+	oglematchers.Matcher
+	expectedValue int
+	x             interface{}
+}
+
+func TestMatcherField(t *testing.T) {
+	m := &otherLayoutMatcher{expectedValue: 17, x: "taco"}
+
+	f, ok := matcherField(m, "x", emptyInterfaceType)
+	if !ok {
+		t.Fatalf("Expected field x to be found")
+	}
+
+	expectEqStr(t, "taco", f.Interface().(string))
+
+	if _, ok := matcherField(m, "expectedValue", reflectValueType); ok {
+		t.Errorf("Expected field of the wrong type to be ignored")
+	}
+
+	if _, ok := matcherField(m, "y", emptyInterfaceType); ok {
+		t.Errorf("Expected missing field to be ignored")
+	}
+}
+
+func TestFormatLinesNested(t *testing.T) {
+	type inner struct {
+		S []int
+	}
+
+	type outer struct {
+		I inner
+		M map[string]bool
+	}
+
+	v := outer{inner{[]int{1}}, map[string]bool{"b": true, "a": false}}
+	expected := []string{
+		"ogletest.outer{",
+		"  I: ogletest.inner{",
+		"    S: []int{",
+		"      1,",
+		"    },",
+		"  },",
+		"  M: map[string]bool{",
+		"    \"a\": false,",
+		"    \"b\": true,",
+		"  },",
+		"}",
+	}
+
+	actual := formatLines(reflect.ValueOf(v))
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected:\n%s\nActual:\n%s",
+			strings.Join(expected, "\n"),
+			strings.Join(actual, "\n"))
+	}
+}
+
+func TestFormatLinesCycle(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+
+	n := &node{}
+	n.Next = n
+
+	actual := formatLines(reflect.ValueOf(n))
+	expectEqStr(t, "  Next: <cycle to *ogletest.node>,", actual[1])
+}
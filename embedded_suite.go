@@ -0,0 +1,246 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jacobsa/ogletest/srcutil"
+)
+
+// The tag for embedded suites whose hooks are chained with those of the suite
+// embedding them.
+const chainTag = "chain"
+
+// The tag for embedded suites whose hooks are replaced by those of the suite
+// embedding them, as usual in Go.
+const noChainTag = "nochain"
+
+// A level in the embedding hierarchy of a test suite: either the suite type
+// itself, or an exported struct type embedded by value within it (possibly
+// transitively). Test methods promoted from embedded levels are named after
+// the type that declares them. The SetUp, TearDown, SetUpTestSuite, and
+// TearDownTestSuite methods declared at each chained level are all called.
+type suiteLevel struct {
+	// The index sequence of the embedded field within the suite struct, as for
+	// reflect.Value.FieldByIndex. Empty for the suite type itself.
+	index []int
+
+	// A pointer to the type at this level.
+	typ reflect.Type
+
+	// Pointers to the types of the levels embedded directly within this one.
+	children []reflect.Type
+
+	// Whether the hooks declared at this level are chained: true for the suite
+	// type itself, and for levels embedded with fields tagged
+	// `ogletest:"chain"` within chained levels.
+	chained bool
+
+	// Pointers to the types of the chained levels embedded directly within
+	// this one.
+	chainedChildren []reflect.Type
+
+	// Pointers to the types of the levels embedded directly within this one
+	// with fields that have no ogletest tag.
+	untaggedChildren []reflect.Type
+}
+
+// Return the levels of the suite with the supplied pointer type, most deeply
+// embedded first and the suite type itself last. Embedded fields at the same
+// depth appear in declaration order.
+func suiteLevels(typ reflect.Type) (levels []suiteLevel) {
+	var visit func(index []int, t reflect.Type, chained bool)
+	visit = func(index []int, t reflect.Type, chained bool) {
+		l := suiteLevel{index: index, typ: reflect.PtrTo(t), chained: chained}
+		if t.Kind() == reflect.Struct {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				if !f.Anonymous || f.PkgPath != "" || f.Type.Kind() != reflect.Struct {
+					continue
+				}
+
+				fieldIndex := append(append([]int{}, index...), i)
+				tag, tagged := f.Tag.Lookup("ogletest")
+				fieldChained := chained && tag == chainTag
+				visit(fieldIndex, f.Type, fieldChained)
+
+				l.children = append(l.children, reflect.PtrTo(f.Type))
+				if fieldChained {
+					l.chainedChildren = append(l.chainedChildren, reflect.PtrTo(f.Type))
+				}
+
+				if !tagged {
+					l.untaggedChildren = append(l.untaggedChildren, reflect.PtrTo(f.Type))
+				}
+			}
+		}
+
+		levels = append(levels, l)
+	}
+
+	visit(nil, typ.Elem(), true)
+	return
+}
+
+// Does the type at this level declare the named method itself, rather than
+// having it promoted from another level? Methods promoted from other embedded
+// fields (such as unexported or pointer ones) count as declared here, since
+// they can only be called through this level.
+func (l suiteLevel) declares(name string) bool {
+	depth, ok := srcutil.MethodDepth(l.typ, name)
+	if !ok {
+		return false
+	}
+
+	if depth == 0 {
+		return true
+	}
+
+	for _, c := range l.children {
+		if _, ok := c.MethodByName(name); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Is this level chained, and does it declare the named hook? As for declares,
+// except that hooks promoted from embedded levels that aren't chained count as
+// declared here, so that they are called as Go would call them: only if no
+// shallower type declares the same hook.
+func (l suiteLevel) declaresHook(name string) bool {
+	if !l.chained {
+		return false
+	}
+
+	depth, ok := srcutil.MethodDepth(l.typ, name)
+	if !ok {
+		return false
+	}
+
+	if depth == 0 {
+		return true
+	}
+
+	for _, c := range l.chainedChildren {
+		if _, ok := c.MethodByName(name); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Return a description of each hook that the type at this level declares
+// itself, hiding a hook of the same name from a type embedded without a tag.
+// It is unclear whether the embedded hook should run too, so the field must
+// say.
+func (l suiteLevel) hiddenHookProblems() (problems []string) {
+	for _, name := range chainedMethods {
+		if depth, ok := srcutil.MethodDepth(l.typ, name); !ok || depth != 0 {
+			continue
+		}
+
+		for _, c := range l.untaggedChildren {
+			if _, ok := c.MethodByName(name); !ok {
+				continue
+			}
+
+			problems = append(
+				problems,
+				fmt.Sprintf(
+					"%s.%s hides %s.%s; tag the embedded field `ogletest:%q` to "+
+						"call both, or `ogletest:%q` if %s.%s calls it itself",
+					l.typ.Elem().Name(),
+					name,
+					c.Elem().Name(),
+					name,
+					chainTag,
+					noChainTag,
+					l.typ.Elem().Name(),
+					name))
+		}
+	}
+
+	return
+}
+
+// Return the receiver for this level within the supplied suite instance.
+func (l suiteLevel) receiver(instance reflect.Value) interface{} {
+	return instance.Elem().FieldByIndex(l.index).Addr().Interface()
+}
+
+// Does any level declare the named hook?
+func hasHook(levels []suiteLevel, name string) bool {
+	for _, l := range levels {
+		if l.declaresHook(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Call f with the receiver within instance of each level that declares the
+// named hook, most deeply embedded first or, if reverse is set, the suite type
+// itself first.
+func callHooks(
+	instance reflect.Value,
+	levels []suiteLevel,
+	name string,
+	reverse bool,
+	f func(receiver interface{})) {
+	for i := range levels {
+		l := levels[i]
+		if reverse {
+			l = levels[len(levels)-1-i]
+		}
+
+		if l.declaresHook(name) {
+			f(l.receiver(instance))
+		}
+	}
+}
+
+// Return the level that declares the named method as it is seen by the suite,
+// i.e. the shallowest one. Returns the suite type's level if none does.
+func declaringLevel(levels []suiteLevel, name string) (level suiteLevel) {
+	level = levels[len(levels)-1]
+	found := false
+	for _, l := range levels {
+		if l.declares(name) && (!found || len(l.index) < len(level.index)) {
+			level = l
+			found = true
+		}
+	}
+
+	return
+}
+
+// Return the prefix to use for the name of the test or benchmark function for
+// the named method: the name of the embedded type that declares it followed by
+// a dot if it is inherited, and empty otherwise.
+func inheritedPrefix(levels []suiteLevel, name string) string {
+	l := declaringLevel(levels, name)
+	if len(l.index) == 0 {
+		return ""
+	}
+
+	return l.typ.Elem().Name() + "."
+}
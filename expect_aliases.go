@@ -19,7 +19,7 @@ import "github.com/jacobsa/oglematchers"
 
 // ExpectEq(e, a) is equivalent to ExpectThat(a, oglematchers.Equals(e)).
 func ExpectEq(expected, actual interface{}, errorParts ...interface{}) {
-	expectThat(actual, oglematchers.Equals(expected), 1, errorParts)
+	expectThat(actual, equalsValue(expected), 1, errorParts)
 }
 
 // ExpectNe(e, a) is equivalent to
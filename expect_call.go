@@ -17,7 +17,6 @@ package ogletest
 
 import (
 	"github.com/jacobsa/oglemock"
-	"runtime"
 )
 
 // ExpectCall expresses an expectation that the method of the given name
@@ -34,22 +33,22 @@ import (
 // This is a shortcut for calling i.MockController.ExpectCall, where i is the
 // TestInfo struct for the currently-running test. Unlike that direct approach,
 // this function automatically sets the correct file name and line number for
-// the expectation.
+// the expectation, skipping over functions marked with Helper.
 func ExpectCall(o oglemock.MockObject, method string) oglemock.PartialExpecation {
 	// Get information about the call site.
-	_, file, lineNumber, ok := runtime.Caller(1)
+	file, lineNumber, ok := callerFileLine(1)
 	if !ok {
-		panic("ExpectCall: runtime.Caller")
+		panic("ExpectCall: callerFileLine")
 	}
 
 	// Grab the current test info.
-	info := currentlyRunningTest
+	info := getCurrentlyRunningTest()
 	if info == nil {
 		panic("ExpectCall: no test info.")
 	}
 
 	// Grab the mock controller.
-	controller := currentlyRunningTest.MockController
+	controller := info.MockController
 	if controller == nil {
 		panic("ExpectCall: no mock controller.")
 	}
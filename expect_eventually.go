@@ -0,0 +1,137 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"time"
+
+	"github.com/jacobsa/oglematchers"
+)
+
+// ExpectEventually repeatedly calls f, waiting interval between calls, until
+// the value it returns matches m or timeout has elapsed. In the latter case it
+// adds a failure record for the last value returned by f, just as ExpectThat
+// would. The final call to f happens at the deadline, however long interval
+// is. errorParts are as for ExpectThat.
+//
+// For example:
+//
+//     ExpectEventually(
+//       func() interface{} { return server.NumConnections() },
+//       Equals(0),
+//       time.Second,
+//       10*time.Millisecond)
+//
+func ExpectEventually(
+	f func() interface{},
+	m oglematchers.Matcher,
+	timeout time.Duration,
+	interval time.Duration,
+	errorParts ...interface{}) {
+	expectEventually(f, m, timeout, interval, 1, errorParts)
+}
+
+// ExpectConsistently repeatedly calls f, waiting interval between calls, until
+// duration has elapsed, confirming that each value it returns matches m. If a
+// value doesn't match, it stops polling and adds a failure record for that
+// value, just as ExpectThat would. f is always called both immediately and at
+// the end of duration, however long interval is. errorParts are as for
+// ExpectThat.
+func ExpectConsistently(
+	f func() interface{},
+	m oglematchers.Matcher,
+	duration time.Duration,
+	interval time.Duration,
+	errorParts ...interface{}) {
+	expectConsistently(
+		f,
+		m,
+		duration,
+		interval,
+		FailureExpectation,
+		1,
+		errorParts)
+}
+
+// The generalized form of ExpectEventually. depth is as for expectThat.
+// Returns passed iff a value returned by f matched.
+func expectEventually(
+	f func() interface{},
+	m oglematchers.Matcher,
+	timeout time.Duration,
+	interval time.Duration,
+	depth int,
+	errorParts []interface{}) (passed bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		x := f()
+		matcherErr := m.Matches(x)
+		if matcherErr == nil {
+			passed = true
+			return
+		}
+
+		// Give up once an attempt at the deadline has failed.
+		if !sleepUntilNextAttempt(deadline, interval) {
+			addMatcherFailure(x, m, matcherErr, FailureTimeout, depth+1, errorParts)
+			return
+		}
+	}
+}
+
+// The generalized form of ExpectConsistently. kind is the kind of failure to
+// record, and depth is as for expectThat. Returns passed iff every value
+// returned by f matched.
+func expectConsistently(
+	f func() interface{},
+	m oglematchers.Matcher,
+	duration time.Duration,
+	interval time.Duration,
+	kind FailureKind,
+	depth int,
+	errorParts []interface{}) (passed bool) {
+	deadline := time.Now().Add(duration)
+	for {
+		x := f()
+		if matcherErr := m.Matches(x); matcherErr != nil {
+			addMatcherFailure(x, m, matcherErr, kind, depth+1, errorParts)
+			return
+		}
+
+		// Stop once a check at the deadline has passed.
+		if !sleepUntilNextAttempt(deadline, interval) {
+			passed = true
+			return
+		}
+	}
+}
+
+// Sleep for the supplied interval, or only until the deadline if that comes
+// sooner, so that the final attempt happens at the deadline. Return false
+// without sleeping if the deadline has already passed.
+func sleepUntilNextAttempt(deadline time.Time, interval time.Duration) bool {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return false
+	}
+
+	if interval > remaining {
+		interval = remaining
+	}
+
+	time.Sleep(interval)
+	return true
+}
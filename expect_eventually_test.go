@@ -0,0 +1,116 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/jacobsa/oglematchers"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// Return a function that returns 1, 2, 3, ... on successive calls, along with
+// a pointer to the number of calls so far.
+func makeCounter() (f func() interface{}, calls *int) {
+	calls = new(int)
+	f = func() interface{} {
+		*calls++
+		return *calls
+	}
+
+	return
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestEventuallyEventuallyMatches(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+	ExpectEventually(f, Equals(3), time.Second, time.Millisecond)
+
+	assertEqInt(t, 0, len(currentlyRunningTest.failureRecords))
+	expectEqInt(t, 3, *calls)
+}
+
+func TestEventuallyTimesOut(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+	ExpectEventually(f, LessThan(0), 10*time.Millisecond, time.Millisecond, "taco")
+
+	assertEqInt(t, 1, len(currentlyRunningTest.failureRecords))
+	record := currentlyRunningTest.failureRecords[0]
+
+	expectEqStr(t, "expect_eventually_test.go", record.FileName)
+	expectEqInt(t, 58, record.LineNumber)
+	expectEqStr(
+		t,
+		fmt.Sprintf("Expected: less than 0\nActual:   %d\ntaco", *calls),
+		record.Error)
+}
+
+func TestConsistentlyAlwaysMatches(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+	ExpectConsistently(f, GreaterThan(0), 10*time.Millisecond, time.Millisecond)
+
+	assertEqInt(t, 0, len(currentlyRunningTest.failureRecords))
+	if *calls < 2 {
+		t.Errorf("Expected several calls, got %d", *calls)
+	}
+}
+
+func TestConsistentlyStopsAtFirstMismatch(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+	ExpectConsistently(f, LessThan(3), time.Second, time.Millisecond)
+
+	assertEqInt(t, 1, len(currentlyRunningTest.failureRecords))
+	record := currentlyRunningTest.failureRecords[0]
+
+	expectEqInt(t, 3, *calls)
+	expectEqStr(t, "Expected: less than 3\nActual:   3", record.Error)
+}
+
+func TestEventuallyTriesAgainAtDeadline(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+	ExpectEventually(f, Equals(2), 20*time.Millisecond, time.Second)
+
+	assertEqInt(t, 0, len(currentlyRunningTest.failureRecords))
+	expectEqInt(t, 2, *calls)
+}
+
+func TestConsistentlyChecksAgainAtDeadline(t *testing.T) {
+	setUpCurrentTest()
+	f, calls := makeCounter()
+
+	start := time.Now()
+	ExpectConsistently(f, GreaterThan(0), 20*time.Millisecond, time.Second)
+	elapsed := time.Since(start)
+
+	assertEqInt(t, 0, len(currentlyRunningTest.failureRecords))
+	expectEqInt(t, 2, *calls)
+	if elapsed < 20*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to return at the deadline, took %v", elapsed)
+	}
+}
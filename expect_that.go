@@ -17,9 +17,7 @@ package ogletest
 
 import (
 	"fmt"
-	"path"
 	"reflect"
-	"runtime"
 
 	"github.com/jacobsa/oglematchers"
 )
@@ -28,7 +26,9 @@ import (
 // failure record to the currently running test if it does not. If additional
 // parameters are supplied, the first will be used as a format string for the
 // later ones, and the user-supplied error message will be added to the test
-// output in the event of a failure.
+// output in the event of a failure. The failure is reported at the line that
+// called ExpectThat, or the line that called the outermost function marked
+// with Helper.
 //
 // For example:
 //
@@ -50,6 +50,17 @@ func expectThat(
 	m oglematchers.Matcher,
 	depth int,
 	errorParts []interface{}) (passed bool) {
+	passed = checkThat(x, m, FailureExpectation, depth+1, errorParts)
+	return
+}
+
+// Like expectThat, but records failures with the supplied kind.
+func checkThat(
+	x interface{},
+	m oglematchers.Matcher,
+	kind FailureKind,
+	depth int,
+	errorParts []interface{}) (passed bool) {
 	// Check whether the value matches. If it does, we are finished.
 	matcherErr := m.Matches(x)
 	if matcherErr == nil {
@@ -57,15 +68,36 @@ func expectThat(
 		return
 	}
 
-	var r FailureRecord
+	addMatcherFailure(x, m, matcherErr, kind, depth+1, errorParts)
+	return
+}
+
+// Report a failure of the supplied kind for the value x, which was rejected by
+// the matcher m with the supplied error. depth is as for expectThat.
+func addMatcherFailure(
+	x interface{},
+	m oglematchers.Matcher,
+	matcherErr error,
+	kind FailureKind,
+	depth int,
+	errorParts []interface{}) {
+	r := FailureRecord{
+		Kind:               kind,
+		Actual:             x,
+		MatcherDescription: m.Description(),
+	}
+
+	if e, ok := expectedValue(m); ok {
+		r.Expected = e
+	}
 
 	// Get information about the call site.
-	var ok bool
-	if _, r.FileName, r.LineNumber, ok = runtime.Caller(depth + 1); !ok {
-		panic("expectThat: runtime.Caller")
+	frames := callerFrames(depth + 1)
+	if len(frames) == 0 {
+		panic("expectThat: callerFrames")
 	}
 
-	r.FileName = path.Base(r.FileName)
+	setLocation(&r, frames)
 
 	// Create an appropriate failure message. Make sure that the expected and
 	// actual values align properly.
@@ -76,25 +108,35 @@ func expectThat(
 
 	r.Error = fmt.Sprintf(
 		"Expected: %s\nActual:   %v%s",
-		m.Description(),
+		r.MatcherDescription,
 		x,
 		relativeClause)
 
+	// Add a diff between the expected and actual values, if it would help.
+	if diff := diffForMatcher(m, x); diff != "" {
+		r.Error = fmt.Sprintf("%s\n%s", r.Error, diff)
+	}
+
 	// Add the user error, if any.
 	if len(errorParts) != 0 {
-		v := reflect.ValueOf(errorParts[0])
-		if v.Kind() != reflect.String {
-			panic(fmt.Sprintf("ExpectThat: invalid format string type %v", v.Kind()))
-		}
-
 		r.Error = fmt.Sprintf(
 			"%s\n%s",
 			r.Error,
-			fmt.Sprintf(v.String(), errorParts[1:]...))
+			formatErrorParts("ExpectThat", errorParts))
 	}
 
 	// Report the failure.
 	AddFailureRecord(r)
+}
 
-	return
+// Format the supplied non-empty user error parts, a format string followed by
+// its arguments, panicking on behalf of the named function if the first isn't
+// a string.
+func formatErrorParts(caller string, errorParts []interface{}) string {
+	v := reflect.ValueOf(errorParts[0])
+	if v.Kind() != reflect.String {
+		panic(fmt.Sprintf("%s: invalid format string type %v", caller, v.Kind()))
+	}
+
+	return fmt.Sprintf(v.String(), errorParts[1:]...)
 }
@@ -17,7 +17,10 @@ package ogletest
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/jacobsa/oglematchers"
 )
@@ -67,22 +70,17 @@ func expectEqStr(t *testing.T, e, c string) {
 ////////////////////////////////////////////////////////////////////////
 
 func TestNoCurrentTest(t *testing.T) {
-	panicked := false
-
-	defer func() {
-		if !panicked {
-			t.Errorf("Expected panic; got none.")
-		}
-	}()
-
-	defer func() {
-		if r := recover(); r != nil {
-			panicked = true
-		}
-	}()
-
 	currentlyRunningTest = nil
+	takeLateFailureRecords()
+
 	ExpectThat(17, Equals(19))
+
+	records := takeLateFailureRecords()
+	assertEqInt(t, 1, len(records))
+	expectEqStr(
+		t,
+		"Failure reported while no test was running:\nExpected: 19\nActual:   17",
+		records[0].Error)
 }
 
 func TestNoFailure(t *testing.T) {
@@ -122,7 +120,7 @@ func TestNoMatchWithoutErrorText(t *testing.T) {
 
 	record := currentlyRunningTest.failureRecords[0]
 	expectEqStr(t, "expect_that_test.go", record.FileName)
-	expectEqInt(t, 119, record.LineNumber)
+	expectEqInt(t, 117, record.LineNumber)
 	expectEqStr(t, "Expected: taco\nActual:   17", record.Error)
 }
 
@@ -166,3 +164,117 @@ func TestAdditionalFailure(t *testing.T) {
 	expectEqStr(t, "Expected: \nActual:   17\ntaco", record1.Error)
 	expectEqStr(t, "Expected: \nActual:   19\nburrito", record2.Error)
 }
+
+func TestFailureRecordDetails(t *testing.T) {
+	setUpCurrentTest()
+	before := time.Now()
+	ExpectThat(17, Equals(19))
+
+	assertEqInt(t, 1, len(currentlyRunningTest.failureRecords))
+	record := currentlyRunningTest.failureRecords[0]
+
+	expectEqStr(t, "expectation", record.Kind.String())
+	expectEqStr(t, "19", record.MatcherDescription)
+	expectEqStr(
+		t,
+		"github.com/jacobsa/ogletest.TestFailureRecordDetails",
+		record.FunctionName)
+
+	if record.Actual != 17 {
+		t.Errorf("Expected actual value 17, got %v", record.Actual)
+	}
+
+	if !strings.HasSuffix(record.FilePath, "/expect_that_test.go") {
+		t.Errorf("Unexpected path: %s", record.FilePath)
+	}
+
+	if !strings.HasPrefix(record.Stack, record.FunctionName+"\n") {
+		t.Errorf("Unexpected stack: %s", record.Stack)
+	}
+
+	if record.Time.Before(before) {
+		t.Errorf("Unexpected time: %v", record.Time)
+	}
+}
+
+func TestFailureRecordExpectedValue(t *testing.T) {
+	setUpCurrentTest()
+	ExpectEq("taco", "burrito")
+	ExpectThat([]int{1}, DeepEquals([]int{2}))
+	ExpectThat("taco", Equals(17))
+	ExpectThat(17, LessThan(17))
+
+	assertEqInt(t, 4, len(currentlyRunningTest.failureRecords))
+	records := currentlyRunningTest.failureRecords
+
+	if records[0].Expected != "taco" {
+		t.Errorf("Unexpected expected value: %v", records[0].Expected)
+	}
+
+	if !reflect.DeepEqual([]int{2}, records[1].Expected) {
+		t.Errorf("Unexpected expected value: %#v", records[1].Expected)
+	}
+
+	if records[2].Expected != 17 {
+		t.Errorf("Unexpected expected value: %#v", records[2].Expected)
+	}
+
+	// Other matchers don't have an expected value.
+	if records[3].Expected != nil {
+		t.Errorf("Unexpected expected value: %#v", records[3].Expected)
+	}
+}
+
+func TestAssertionFailureKind(t *testing.T) {
+	setUpCurrentTest()
+	func() {
+		defer func() { recover() }()
+		AssertThat(17, Equals(19))
+	}()
+
+	assertEqInt(t, 1, len(currentlyRunningTest.failureRecords))
+	record := currentlyRunningTest.failureRecords[0]
+
+	expectEqStr(t, "assertion", record.Kind.String())
+}
+
+func TestFailureAfterTestFinished(t *testing.T) {
+	takeLateFailureRecords()
+
+	var ti *TestInfo
+	failures, _ := runTestFunction(
+		"FooTest.DoesBar",
+		TestFunction{
+			Name:  "DoesBar",
+			SetUp: func(x *TestInfo) { ti = x },
+			Run:   func() {},
+		})
+
+	assertEqInt(t, 0, len(failures))
+
+	// A goroutine that outlives the test may still hold it.
+	ti.addFailureRecord(FailureRecord{Error: "taco"})
+
+	records := takeLateFailureRecords()
+	assertEqInt(t, 1, len(records))
+	expectEqStr(
+		t,
+		"Failure reported after test FooTest.DoesBar finished:\ntaco",
+		records[0].Error)
+}
+
+func TestFailureAfterRunFinished(t *testing.T) {
+	currentlyRunningTest = nil
+	startLateFailures()
+	finishLateFailures()
+	defer startLateFailures()
+
+	AddFailure("taco")
+
+	records := startLateFailures()
+	assertEqInt(t, 1, len(records))
+	expectEqStr(
+		t,
+		"Failure reported after the previous run finished:\ntaco",
+		records[0].Error)
+}
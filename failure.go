@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"path"
 	"runtime"
+	"sync"
+	"time"
 )
 
 // FailureRecord represents a single failed expectation or assertion for a
@@ -28,6 +30,11 @@ type FailureRecord struct {
 	// The file name within which the expectation failed, e.g. "foo_test.go".
 	FileName string
 
+	// The full path of the file within which the expectation failed, e.g.
+	// "/home/jacobsa/go/src/foo/foo_test.go", if known. The runner uses this to
+	// print the source code around the failure.
+	FilePath string
+
 	// The line number at which the expectation failed.
 	LineNumber int
 
@@ -42,40 +49,247 @@ type FailureRecord struct {
 	//     Actual:   "taco", which is not numeric
 	//
 	Error string
+
+	// The context messages of the scopes (see Scope) that were open when the
+	// failure was reported, outermost first. AddFailureRecord fills this in if
+	// it is nil.
+	Scopes []string
+
+	// The fully-qualified name of the function within which the failure was
+	// reported, e.g. "github.com/jacobsa/foo_test.(*FooTest).DoesSomething",
+	// if known.
+	FunctionName string
+
+	// The stack at the point the failure was reported, formatted in the style
+	// of a panic stack trace and excluding the frames of the ogletest runner,
+	// if known.
+	Stack string
+
+	// The time at which the failure was reported. AddFailureRecord fills this
+	// in if it is zero.
+	Time time.Time
+
+	// The sort of failure this is.
+	Kind FailureKind
+
+	// For failures from matchers (see ExpectThat), the value that didn't match,
+	// the matcher's description, and the value that the matcher expected, if
+	// it is an equality matcher and the value is available.
+	Actual             interface{}
+	MatcherDescription string
+	Expected           interface{}
+
+	// For failures from property checks (see ExpectForAll), the seed of the
+	// random source used to generate inputs, and the minimal failing input.
+	Seed  int64
+	Input interface{}
+}
+
+// FailureKind describes what caused a failure record.
+type FailureKind int
+
+const (
+	// A failure reported directly with AddFailure or AddFailureRecord.
+	FailureOther FailureKind = iota
+
+	// A failed expectation, e.g. from ExpectThat or ExpectEq.
+	FailureExpectation
+
+	// A failed assertion, e.g. from AssertThat or AssertEq.
+	FailureAssertion
+
+	// A panic from the test, other than one caused by a failed assertion.
+	FailurePanic
+
+	// An error from a mock controller, e.g. an unexpected call.
+	FailureMock
+
+	// A polling expectation or assertion, e.g. ExpectEventually, that timed
+	// out.
+	FailureTimeout
+
+	// A property, checked with ExpectForAll, that failed for some input.
+	FailureProperty
+)
+
+func (k FailureKind) String() string {
+	switch k {
+	case FailureOther:
+		return "other"
+	case FailureExpectation:
+		return "expectation"
+	case FailureAssertion:
+		return "assertion"
+	case FailurePanic:
+		return "panic"
+	case FailureMock:
+		return "mock"
+	case FailureTimeout:
+		return "timeout"
+	case FailureProperty:
+		return "property"
+	}
+
+	return fmt.Sprintf("FailureKind(%d)", int(k))
 }
 
 // Record a failure for the currently running test (and continue running it).
 // Most users will want to use ExpectThat, ExpectEq, etc. instead of this
 // function. Those that do want to report arbitrary errors will probably be
 // satisfied with AddFailure, which is easier to use.
+//
+// This function may be called from any goroutine, and records the failure for
+// whichever test is running at the time. Start goroutines with TestInfo.Go to
+// make sure that is the test that started them: the runner doesn't move on to
+// the next test until they have returned. If no test is running (for example
+// because the failure comes from a goroutine started with a plain go statement
+// that outlived its test), the failure is reported by the runner as having
+// happened after the test finished.
 func AddFailureRecord(r FailureRecord) {
-	currentlyRunningTest.mu.Lock()
-	defer currentlyRunningTest.mu.Unlock()
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+
+	ti := getCurrentlyRunningTest()
+	if ti == nil {
+		addLateFailureRecord(r)
+		return
+	}
+
+	ti.addFailureRecord(r)
+}
+
+var lateFailuresMu sync.Mutex
+
+// Failure records reported while no test was running, waiting to be printed
+// by the runner.
+//
+// GUARDED_BY(lateFailuresMu)
+var lateFailureRecords []FailureRecord
+
+// The full name of the test that most recently finished in the current suite,
+// or the empty string if none.
+//
+// GUARDED_BY(lateFailuresMu)
+var lastFinishedTest string
+
+// Set once a run of test suites has finished, until the next one starts.
+//
+// GUARDED_BY(lateFailuresMu)
+var betweenRuns bool
+
+func addLateFailureRecord(r FailureRecord) {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	if betweenRuns && lastFinishedTest == "" {
+		r.Error = fmt.Sprintf(
+			"Failure reported after the previous run finished:\n%s",
+			r.Error)
+
+		lateFailureRecords = append(lateFailureRecords, r)
+		return
+	}
+
+	appendLateFailureRecord(lastFinishedTest, r)
+}
+
+// Add a late failure record reported by a goroutine belonging to the named
+// test after the test finished.
+func addFailureAfterTest(name string, r FailureRecord) {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	appendLateFailureRecord(name, r)
+}
 
-	currentlyRunningTest.failureRecords = append(
-		currentlyRunningTest.failureRecords,
-		r)
+// LOCKS_REQUIRED(lateFailuresMu)
+func appendLateFailureRecord(testName string, r FailureRecord) {
+	if testName == "" {
+		r.Error = fmt.Sprintf(
+			"Failure reported while no test was running:\n%s",
+			r.Error)
+	} else {
+		r.Error = fmt.Sprintf(
+			"Failure reported after test %s finished:\n%s",
+			testName,
+			r.Error)
+	}
+
+	lateFailureRecords = append(lateFailureRecords, r)
+}
+
+// Set the name used to label late failure records reported from now on.
+func setLastFinishedTest(name string) {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	lastFinishedTest = name
+}
+
+// Begin a run of test suites, returning the late failure records reported
+// since the previous run finished printing them.
+func startLateFailures() (records []FailureRecord) {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	records = lateFailureRecords
+	lateFailureRecords = nil
+	lastFinishedTest = ""
+	betweenRuns = false
+	return
+}
+
+// End a run of test suites. Late failure records reported from now on are
+// printed by the next run, if any.
+func finishLateFailures() {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	lastFinishedTest = ""
+	betweenRuns = true
+}
+
+// Remove and return all late failure records reported so far.
+func takeLateFailureRecords() (records []FailureRecord) {
+	lateFailuresMu.Lock()
+	defer lateFailuresMu.Unlock()
+
+	records = lateFailureRecords
+	lateFailureRecords = nil
+	return
 }
 
 // Call AddFailureRecord with a record whose file name and line number come
 // from the caller of this function, and whose error string is created by
-// calling fmt.Sprintf using the arguments to this function.
+// calling fmt.Sprintf using the arguments to this function. Functions marked
+// with Helper are skipped when finding the caller.
 func AddFailure(format string, a ...interface{}) {
 	r := FailureRecord{
 		Error: fmt.Sprintf(format, a...),
 	}
 
 	// Get information about the call site.
-	var ok bool
-	if _, r.FileName, r.LineNumber, ok = runtime.Caller(1); !ok {
+	frames := callerFrames(1)
+	if len(frames) == 0 {
 		panic("Can't find caller")
 	}
 
-	r.FileName = path.Base(r.FileName)
+	setLocation(&r, frames)
 
 	AddFailureRecord(r)
 }
 
+// Fill in the location fields of the supplied record from a stack, innermost
+// frame first, as returned by callerFrames.
+func setLocation(r *FailureRecord, frames []runtime.Frame) {
+	r.FilePath = frames[0].File
+	r.FileName = path.Base(r.FilePath)
+	r.LineNumber = frames[0].Line
+	r.FunctionName = frames[0].Function
+	r.Stack = formatFrames(frames)
+}
+
 // A sentinel type that is used in a conspiracy between AbortTest and runTests.
 // If runTests sees an abortError as the value given to a panic() call, it will
 // avoid printing the panic error.
@@ -85,6 +299,15 @@ type abortError struct {
 // Immediately stop executing the running test, causing it to fail with the
 // failures previously recorded. Behavior is undefined if no failures have been
 // recorded.
+//
+// When called from a goroutine started with TestInfo.Go, this stops only that
+// goroutine. When called from a goroutine started with a plain go statement,
+// it cannot stop the test either; instead it stops the calling goroutine with
+// runtime.Goexit, running its deferred calls.
 func AbortTest() {
+	if !canPanicToAbort() {
+		runtime.Goexit()
+	}
+
 	panic(abortError{})
 }
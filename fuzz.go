@@ -0,0 +1,284 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Test suites that implement this interface have special meaning to
+// RegisterTestSuite.
+type FuzzSeedsInterface interface {
+	// Return the seed corpora for the suite's fuzz methods, keyed by method
+	// name (e.g. "FuzzParse"). Each seed is a list of arguments for the method,
+	// whose types must match its parameters exactly. This method is called on a
+	// zero value of the test suite type when the suite is registered.
+	FuzzSeeds() map[string][][]interface{}
+}
+
+// The types that testing.F supports as fuzz arguments.
+var fuzzArgTypes = map[reflect.Type]bool{
+	reflect.TypeOf(""):         true,
+	reflect.TypeOf([]byte{}):   true,
+	reflect.TypeOf(int(0)):     true,
+	reflect.TypeOf(int8(0)):    true,
+	reflect.TypeOf(int16(0)):   true,
+	reflect.TypeOf(int32(0)):   true,
+	reflect.TypeOf(int64(0)):   true,
+	reflect.TypeOf(uint(0)):    true,
+	reflect.TypeOf(uint8(0)):   true,
+	reflect.TypeOf(uint16(0)):  true,
+	reflect.TypeOf(uint32(0)):  true,
+	reflect.TypeOf(uint64(0)):  true,
+	reflect.TypeOf(float32(0)): true,
+	reflect.TypeOf(float64(0)): true,
+	reflect.TypeOf(false):      true,
+}
+
+// Is the supplied method a fuzz method, i.e. is its name of the form FuzzFoo,
+// and does it take one or more arguments of types supported by testing.F and
+// return nothing?
+func isFuzzMethod(method reflect.Method) bool {
+	t := method.Type
+	if !strings.HasPrefix(method.Name, "Fuzz") ||
+		method.Name == "Fuzz" ||
+		t.NumIn() < 2 ||
+		t.NumOut() != 0 {
+		return false
+	}
+
+	for i := 1; i < t.NumIn(); i++ {
+		if !fuzzArgTypes[t.In(i)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Is the supplied method, which is not a fuzz method, apparently meant to be
+// one? That is, is its name of the form FuzzFoo, and does it take arguments
+// that a test method can't?
+func looksLikeFuzzMethod(method reflect.Method) bool {
+	return strings.HasPrefix(method.Name, "Fuzz") &&
+		method.Name != "Fuzz" &&
+		method.Type.NumIn() >= 2 &&
+		checkTestMethod(method) != ""
+}
+
+// Return a description of the problem with the supplied method, for which
+// looksLikeFuzzMethod returns true.
+func fuzzMethodProblem(method reflect.Method) string {
+	return fmt.Sprintf(
+		"%s has signature %s; fuzz methods must take one or more arguments of "+
+			"types supported by testing.F (string, []byte, bool, and numeric "+
+			"types) and return nothing",
+		method.Name,
+		methodSignature(method))
+}
+
+// Return descriptions of any problems with the supplied seed corpora for the
+// supplied fuzz methods.
+func checkFuzzSeeds(
+	methods []reflect.Method,
+	seeds map[string][][]interface{}) (problems []string) {
+	byName := make(map[string]reflect.Method)
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+
+	// Check in a predictable order.
+	var names []string
+	for name := range seeds {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		m, ok := byName[name]
+		if !ok {
+			problems = append(
+				problems,
+				fmt.Sprintf("FuzzSeeds lists unknown fuzz method %s", name))
+			continue
+		}
+
+		for i, seed := range seeds[name] {
+			if problem := checkFuzzSeed(m, seed); problem != "" {
+				problems = append(
+					problems,
+					fmt.Sprintf("FuzzSeeds entry %d for %s %s", i, name, problem))
+			}
+		}
+	}
+
+	return
+}
+
+// Return a description of the problem with the supplied seed for the supplied
+// fuzz method, or the empty string if there is none.
+func checkFuzzSeed(method reflect.Method, seed []interface{}) string {
+	t := method.Type
+	if len(seed) != t.NumIn()-1 {
+		return fmt.Sprintf(
+			"has %d arguments; expected %d",
+			len(seed),
+			t.NumIn()-1)
+	}
+
+	for i, arg := range seed {
+		if reflect.TypeOf(arg) != t.In(i+1) {
+			return fmt.Sprintf(
+				"has argument %d of type %v; expected %v",
+				i,
+				reflect.TypeOf(arg),
+				t.In(i+1))
+		}
+	}
+
+	return ""
+}
+
+// Return a function taking the arguments of the supplied fuzz method (minus
+// its receiver), which calls the method on the receiver returned by
+// getInstance.
+func makeFuzzFunction(
+	method reflect.Method,
+	getInstance func() reflect.Value) interface{} {
+	var in []reflect.Type
+	for i := 1; i < method.Type.NumIn(); i++ {
+		in = append(in, method.Type.In(i))
+	}
+
+	ft := reflect.FuncOf(in, nil, false)
+	f := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		runFuzzMethod(getInstance(), method, args)
+		return nil
+	})
+
+	return f.Interface()
+}
+
+// Call the supplied fuzz method on the supplied suite instance.
+func runFuzzMethod(suite reflect.Value, method reflect.Method, args []reflect.Value) {
+	method.Func.Call(append([]reflect.Value{suite}, args...))
+}
+
+// RunFuzzTarget runs the registered fuzz target with the supplied name, of the
+// form "FooTest.Parse", using the supplied testing.F. It is the bridge between
+// ogletest fuzz methods and `go test -fuzz`, so each target needs its own fuzz
+// function:
+//
+//     func FuzzParse(f *testing.F) { ogletest.RunFuzzTarget(f, "FooTest.Parse") }
+//
+// Fuzz methods are declared on a suite with names starting with "Fuzz" and
+// take one or more arguments of the types supported by testing.F. Their seed
+// corpora are given by the suite's FuzzSeeds method, if any:
+//
+//     func (t *FooTest) FuzzSeeds() map[string][][]interface{} {
+//       return map[string][][]interface{}{
+//         "FuzzParse": {{"taco", 17}, {"", 0}},
+//       }
+//     }
+//
+//     func (t *FooTest) FuzzParse(s string, n int) {
+//       p, err := t.parser.Parse(s, n)
+//       AssertEq(nil, err)
+//       ExpectThat(p.String(), Equals(s))
+//     }
+//
+// The suite's SetUpTestSuite and TearDownTestSuite methods run once around the
+// target, and SetUp and TearDown around each input. Failures reported with
+// ExpectThat, AssertThat, etc. fail the input with the ogletest failure
+// message, as do panics.
+//
+// Panics if there is no such target.
+func RunFuzzTarget(f *testing.F, name string) {
+	suite, target, ok := findFuzzTarget(name)
+	if !ok {
+		panic("RunFuzzTarget: unknown fuzz target: " + name)
+	}
+
+	runFuzzTarget(f, suite, target)
+}
+
+// Find the registered fuzz target with the supplied full name.
+func findFuzzTarget(name string) (suite TestSuite, target FuzzTarget, ok bool) {
+	for _, suite = range registeredSuites {
+		for _, target = range suite.FuzzTargets {
+			if suite.Name+"."+target.Name == name {
+				ok = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// runFuzzTarget does the real work of RunFuzzTarget.
+func runFuzzTarget(f *testing.F, suite TestSuite, target FuzzTarget) {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	if suite.SetUp != nil {
+		suite.SetUp()
+	}
+
+	if suite.TearDown != nil {
+		defer suite.TearDown()
+	}
+
+	for _, seed := range target.Seeds {
+		f.Add(seed...)
+	}
+
+	// Build a function for f.Fuzz, taking a *testing.T followed by the fuzzed
+	// arguments.
+	run := reflect.ValueOf(target.Run)
+	in := []reflect.Type{reflect.TypeOf((*testing.T)(nil))}
+	for i := 0; i < run.Type().NumIn(); i++ {
+		in = append(in, run.Type().In(i))
+	}
+
+	// Report failures through t, since under -fuzz only what is logged to t
+	// reaches the user; the output of fuzz workers is discarded. t labels each
+	// with a location in this file, so the message leads with the user's.
+	ff := reflect.MakeFunc(
+		reflect.FuncOf(in, nil, false),
+		func(args []reflect.Value) []reflect.Value {
+			t := args[0].Interface().(*testing.T)
+			body := func() { run.Call(args[1:]) }
+			runIsolated(
+				target.SetUp,
+				body,
+				target.TearDown,
+				func(failures []FailureRecord) {
+					for _, r := range failures {
+						t.Errorf("%s:%d:\n%s", displayPath(r), r.LineNumber, r.Error)
+					}
+				})
+
+			return nil
+		})
+
+	f.Fuzz(ff.Interface())
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// The arguments most recently passed to fuzzSeedsTest.FuzzParse.
+var fuzzSeedsTestArgs string
+
+type fuzzSeedsTest struct{}
+
+func (t *fuzzSeedsTest) FuzzSeeds() map[string][][]interface{} {
+	return map[string][][]interface{}{
+		"FuzzParse": {{"burrito", 19}},
+	}
+}
+
+func (t *fuzzSeedsTest) FuzzParse(s string, n int) {
+	fuzzSeedsTestArgs = fmt.Sprintf("%s %d", s, n)
+}
+
+type badFuzzSeedsTest struct{}
+
+func (t *badFuzzSeedsTest) FuzzSeeds() map[string][][]interface{} {
+	return map[string][][]interface{}{
+		"FuzzParse": {{"taco", 17}, {"taco"}, {"taco", int64(17)}},
+		"FuzzPrase": {{"taco", 17}},
+	}
+}
+
+func (t *badFuzzSeedsTest) FuzzParse(s string, n int) {}
+
+type badFuzzArgsTest struct{}
+
+func (t *badFuzzArgsTest) FuzzMap(m map[string]int)   {}
+func (t *badFuzzArgsTest) FuzzResult(s string) error  { return nil }
+func (t *badFuzzArgsTest) FuzzyMatching(ti *TestInfo) {}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestFuzzTargets(t *testing.T) {
+	suite := makeTestSuite("RegisterTestSuite", &fuzzSeedsTest{})
+
+	if len(suite.TestFunctions) != 0 {
+		t.Fatalf("Expected no test functions, got %d", len(suite.TestFunctions))
+	}
+
+	if len(suite.FuzzTargets) != 1 {
+		t.Fatalf("Expected one fuzz target, got %d", len(suite.FuzzTargets))
+	}
+
+	ft := suite.FuzzTargets[0]
+	expectEqStr(t, "Parse", ft.Name)
+
+	if len(ft.Seeds) != 1 {
+		t.Fatalf("Expected one seed, got %d", len(ft.Seeds))
+	}
+
+	// The run function should call the method with a fresh instance.
+	ft.SetUp(newTestInfo())
+	ft.Run.(func(string, int))("taco", 17)
+	expectEqStr(t, "taco 17", fuzzSeedsTestArgs)
+}
+
+func TestBadFuzzSeeds(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &badFuzzSeedsTest{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in badFuzzSeedsTest:\n"+
+			"\tFuzzSeeds entry 1 for FuzzParse has 1 arguments; expected 2\n"+
+			"\tFuzzSeeds entry 2 for FuzzParse has argument 1 of type int64; "+
+			"expected int\n"+
+			"\tFuzzSeeds lists unknown fuzz method FuzzPrase",
+		msg)
+}
+
+func TestBadFuzzMethodSignatures(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &badFuzzArgsTest{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in badFuzzArgsTest:\n"+
+			"\tFuzzMap has signature func(map[string]int); fuzz methods must take "+
+			"one or more arguments of types supported by testing.F (string, "+
+			"[]byte, bool, and numeric types) and return nothing\n"+
+			"\tFuzzResult has signature func(string) error; fuzz methods must take "+
+			"one or more arguments of types supported by testing.F (string, "+
+			"[]byte, bool, and numeric types) and return nothing",
+		msg)
+}
@@ -0,0 +1,339 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+)
+
+// A Generator produces random values for ExpectForAll, and simplifies failing
+// ones so that the smallest failing input can be reported.
+type Generator interface {
+	// Generate a random value of type t using the supplied source. size
+	// bounds the length of generated collections and the magnitude of
+	// generated numbers; it grows over the course of a property check.
+	Generate(t reflect.Type, r *rand.Rand, size int) reflect.Value
+
+	// Return values that are simpler than v, most aggressively simplified
+	// first, or nil if v can't be simplified.
+	Shrink(v reflect.Value) []reflect.Value
+}
+
+// Arbitrary returns a generator for values of any type built from booleans,
+// numbers, strings, slices, arrays, maps, pointers, and structs (whose
+// unexported fields are left as zero values). Other types cause the generator
+// to panic.
+func Arbitrary() Generator {
+	return arbitraryGenerator{}
+}
+
+type arbitraryGenerator struct{}
+
+func (g arbitraryGenerator) Generate(
+	t reflect.Type,
+	r *rand.Rand,
+	size int) (v reflect.Value) {
+	v = reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(r.Int63n(int64(2*size+1)) - int64(size))
+
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		v.SetUint(uint64(r.Int63n(int64(size + 1))))
+
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat((2*r.Float64() - 1) * float64(size))
+
+	case reflect.String:
+		runes := make([]rune, r.Intn(size+1))
+		for i := range runes {
+			runes[i] = rune(' ' + r.Intn('~'-' '+1))
+		}
+
+		v.SetString(string(runes))
+
+	case reflect.Slice:
+		n := r.Intn(size + 1)
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := 0; i < n; i++ {
+			v.Index(i).Set(g.Generate(t.Elem(), r, size/2))
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).Set(g.Generate(t.Elem(), r, size/2))
+		}
+
+	case reflect.Map:
+		n := r.Intn(size + 1)
+		v.Set(reflect.MakeMap(t))
+		for i := 0; i < n; i++ {
+			v.SetMapIndex(
+				g.Generate(t.Key(), r, size/2),
+				g.Generate(t.Elem(), r, size/2))
+		}
+
+	case reflect.Ptr:
+		// Generate nil sometimes, and always once size runs out, so that
+		// recursive types terminate.
+		if size > 0 && r.Intn(size+1) != 0 {
+			v.Set(reflect.New(t.Elem()))
+			v.Elem().Set(g.Generate(t.Elem(), r, size/2))
+		}
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath == "" {
+				v.Field(i).Set(g.Generate(t.Field(i).Type, r, size))
+			}
+		}
+
+	default:
+		panic(fmt.Sprintf("Arbitrary: unsupported type %v", t))
+	}
+
+	return
+}
+
+func (g arbitraryGenerator) Shrink(v reflect.Value) (out []reflect.Value) {
+	t := v.Type()
+
+	// Return a new value of type t set to x.
+	value := func(x interface{}) reflect.Value {
+		nv := reflect.New(t).Elem()
+		nv.Set(reflect.ValueOf(x).Convert(t))
+		return nv
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			out = append(out, value(false))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := v.Int()
+		for _, c := range shrinkInt(x) {
+			out = append(out, value(c))
+		}
+
+	case reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		x := v.Uint()
+		if x != 0 {
+			out = append(out, value(uint64(0)))
+		}
+
+		if x > 2 {
+			out = append(out, value(x/2))
+		}
+
+		if x > 1 {
+			out = append(out, value(x-1))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		x := v.Float()
+		if x != 0 {
+			out = append(out, value(0.0))
+		}
+
+		if x != float64(int64(x)) {
+			out = append(out, value(float64(int64(x))))
+		}
+
+		if x > 1 || x < -1 {
+			out = append(out, value(x/2))
+		}
+
+	case reflect.String:
+		// Shrink as a slice of runes, removing but not changing them.
+		runes := reflect.ValueOf([]rune(v.String()))
+		for _, c := range g.shrinkSlice(runes, false) {
+			out = append(out, value(string(c.Interface().([]rune))))
+		}
+
+	case reflect.Slice:
+		out = g.shrinkSlice(v, true)
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			for _, c := range g.Shrink(v.Index(i)) {
+				nv := reflect.New(t).Elem()
+				nv.Set(v)
+				nv.Index(i).Set(c)
+				out = append(out, nv)
+			}
+		}
+
+	case reflect.Map:
+		out = g.shrinkMap(v)
+
+	case reflect.Ptr:
+		if !v.IsNil() {
+			out = append(out, reflect.Zero(t))
+			for _, c := range g.Shrink(v.Elem()) {
+				nv := reflect.New(t.Elem())
+				nv.Elem().Set(c)
+				out = append(out, nv)
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+
+			for _, c := range g.Shrink(v.Field(i)) {
+				nv := reflect.New(t).Elem()
+				nv.Set(v)
+				nv.Field(i).Set(c)
+				out = append(out, nv)
+			}
+		}
+	}
+
+	return
+}
+
+// Return simpler values than x: zero, half of x, and x moved one step towards
+// zero.
+func shrinkInt(x int64) (out []int64) {
+	if x == 0 {
+		return
+	}
+
+	out = append(out, 0)
+	if x/2 != 0 {
+		out = append(out, x/2)
+	}
+
+	if x < 0 {
+		out = append(out, -x)
+	}
+
+	if x > 1 || x < -1 {
+		if x > 0 {
+			out = append(out, x-1)
+		} else {
+			out = append(out, x+1)
+		}
+	}
+
+	return
+}
+
+// Return a copy of the slice v with elements [i, j) removed.
+func removeRange(v reflect.Value, i, j int) reflect.Value {
+	n := v.Len() - (j - i)
+	nv := reflect.MakeSlice(v.Type(), 0, n)
+	nv = reflect.AppendSlice(nv, v.Slice(0, i))
+	nv = reflect.AppendSlice(nv, v.Slice(j, v.Len()))
+	return nv
+}
+
+// Shrink a slice: first by emptying it, then by removing halves, then single
+// elements, and finally (if shrinkElems is set) by shrinking each element.
+func (g arbitraryGenerator) shrinkSlice(
+	v reflect.Value,
+	shrinkElems bool) (out []reflect.Value) {
+	n := v.Len()
+	if n == 0 {
+		return
+	}
+
+	out = append(out, reflect.MakeSlice(v.Type(), 0, 0))
+	if n > 1 {
+		out = append(out, removeRange(v, n/2, n), removeRange(v, 0, n/2))
+	}
+
+	for i := 0; i < n && n > 1; i++ {
+		out = append(out, removeRange(v, i, i+1))
+	}
+
+	for i := 0; i < n && shrinkElems; i++ {
+		for _, c := range g.Shrink(v.Index(i)) {
+			nv := reflect.MakeSlice(v.Type(), n, n)
+			reflect.Copy(nv, v)
+			nv.Index(i).Set(c)
+			out = append(out, nv)
+		}
+	}
+
+	return
+}
+
+// Shrink a map: first by emptying it, then by removing single entries, and
+// finally by shrinking each value. Keys are visited in a fixed order so that
+// shrinking is deterministic.
+func (g arbitraryGenerator) shrinkMap(v reflect.Value) (out []reflect.Value) {
+	if v.Len() == 0 {
+		return
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%#v", keys[i].Interface()) <
+			fmt.Sprintf("%#v", keys[j].Interface())
+	})
+
+	// Return a copy of v, minus the supplied key if valid.
+	copyMap := func(skip reflect.Value) reflect.Value {
+		nv := reflect.MakeMap(v.Type())
+		for _, k := range keys {
+			if skip.IsValid() && k.Interface() == skip.Interface() {
+				continue
+			}
+
+			nv.SetMapIndex(k, v.MapIndex(k))
+		}
+
+		return nv
+	}
+
+	out = append(out, reflect.MakeMap(v.Type()))
+	if len(keys) > 1 {
+		for _, k := range keys {
+			out = append(out, copyMap(k))
+		}
+	}
+
+	for _, k := range keys {
+		for _, c := range g.Shrink(v.MapIndex(k)) {
+			nv := copyMap(reflect.Value{})
+			nv.SetMapIndex(k, c)
+			out = append(out, nv)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,205 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var fUpdateGolden = flag.Bool(
+	"ogletest.update_golden",
+	false,
+	"Write the actual values passed to ExpectMatchesGolden to their golden "+
+		"files rather than comparing against them.")
+
+// The directory, relative to the package being tested, in which golden files
+// live. Overridden by tests.
+var goldenDir = "testdata"
+
+// A Normalizer transforms output before it is compared against a golden file,
+// typically to remove details such as paths and timings that change from run
+// to run.
+type Normalizer func(s string) string
+
+// ReplaceRegexp returns a normalizer that replaces matches for the supplied
+// regular expression with repl, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString. It panics if the expression is invalid.
+func ReplaceRegexp(expr string, repl string) Normalizer {
+	re := regexp.MustCompile(expr)
+	return func(s string) string {
+		return re.ReplaceAllString(s, repl)
+	}
+}
+
+// NormalizePaths returns a normalizer that replaces the directories of
+// absolute paths to files with /some/path/, leaving the file name and any line
+// number in place.
+func NormalizePaths() Normalizer {
+	return ReplaceRegexp(`(?:/[\w.+-]+)+/([\w+-]+(?:\.\w+)+)`, "/some/path/$1")
+}
+
+// NormalizeTimings returns a normalizer that replaces durations such as
+// "1.234s" and "56ms" with "1.23s".
+func NormalizeTimings() Normalizer {
+	return ReplaceRegexp(`\b\d+(?:\.\d+)?(?:ns|us|µs|ms|s)\b`, "1.23s")
+}
+
+// ExpectMatchesGolden checks that actual, which must be a string or a byte
+// slice, matches the contents of the golden file with the given name in the
+// package's testdata directory, after being passed through the supplied
+// normalizers in order. On a mismatch it reports a diff between the two.
+//
+// When run with --ogletest.update_golden, ExpectMatchesGolden instead writes
+// the normalized value to the golden file, creating it if necessary.
+//
+// For example:
+//
+//     ExpectMatchesGolden("report.txt", buf.String(), NormalizeTimings())
+//
+func ExpectMatchesGolden(
+	name string,
+	actual interface{},
+	normalizers ...Normalizer) {
+	expectMatchesGolden(name, actual, normalizers, 1)
+}
+
+// The generalized form of ExpectMatchesGolden. depth is as for expectThat.
+// Returns passed iff the value matched (or the golden file was updated).
+func expectMatchesGolden(
+	name string,
+	actual interface{},
+	normalizers []Normalizer,
+	depth int) (passed bool) {
+	var a string
+	switch v := actual.(type) {
+	case string:
+		a = v
+	case []byte:
+		a = string(v)
+	default:
+		panic(fmt.Sprintf(
+			"ExpectMatchesGolden: expected a string or []byte, got %T",
+			actual))
+	}
+
+	for _, n := range normalizers {
+		a = n(a)
+	}
+
+	path := filepath.Join(goldenDir, filepath.FromSlash(name))
+
+	// Update the file if requested.
+	if *fUpdateGolden {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, []byte(a), 0644)
+		}
+
+		if err != nil {
+			addGoldenFailure(
+				fmt.Sprintf("Writing golden file %s: %v", path, err),
+				depth+1)
+			return
+		}
+
+		passed = true
+		return
+	}
+
+	// Otherwise compare against it.
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		addGoldenFailure(
+			fmt.Sprintf(
+				"Golden file %s doesn't exist; rerun with "+
+					"--ogletest.update_golden to create it.",
+				path),
+			depth+1)
+		return
+	}
+
+	if err != nil {
+		addGoldenFailure(
+			fmt.Sprintf("Reading golden file %s: %v", path, err),
+			depth+1)
+		return
+	}
+
+	e := string(contents)
+	if e == a {
+		passed = true
+		return
+	}
+
+	msg := mismatchMessage(
+		fmt.Sprintf(
+			"Doesn't match golden file %s; rerun with --ogletest.update_golden "+
+				"to update it.",
+			path),
+		e,
+		a)
+
+	addGoldenFailure(msg, depth+1)
+	return
+}
+
+// DiffText describes the differences between the expected and actual text for
+// a failure message, in the format used by ExpectMatchesGolden: a line by line
+// diff, or the quoted text itself if a diff wouldn't help (e.g. if both are
+// single lines). The --ogletest.diff_context and --ogletest.diff_max_lines
+// flags apply.
+func DiffText(expected string, actual string) string {
+	diff := diffLines(
+		strings.Split(expected, "\n"),
+		strings.Split(actual, "\n"),
+		*fDiffContext,
+		*fDiffMaxLines)
+
+	if diff != "" {
+		return diff
+	}
+
+	return fmt.Sprintf("Expected: %q\nActual:   %q", expected, actual)
+}
+
+// Return a failure message made up of the supplied header and DiffText for
+// the expected and actual text.
+func mismatchMessage(header string, e string, a string) string {
+	return fmt.Sprintf("%s\n%s", header, DiffText(e, a))
+}
+
+// Add an expectation failure with the supplied message. depth is as for
+// expectThat.
+func addGoldenFailure(msg string, depth int) {
+	r := FailureRecord{
+		Kind:  FailureExpectation,
+		Error: msg,
+	}
+
+	frames := callerFrames(depth + 1)
+	if len(frames) == 0 {
+		panic("expectMatchesGolden: callerFrames")
+	}
+
+	setLocation(&r, frames)
+	AddFailureRecord(r)
+}
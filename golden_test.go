@@ -0,0 +1,162 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// Point goldenDir at a fresh temporary directory, returning a function that
+// restores it and removes the directory.
+func useTempGoldenDir(t *testing.T) (dir string, restore func()) {
+	dir, err := ioutil.TempDir("", "golden_test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+
+	old := goldenDir
+	goldenDir = dir
+	restore = func() {
+		goldenDir = old
+		os.RemoveAll(dir)
+	}
+
+	return
+}
+
+// Call ExpectMatchesGolden in a fresh test, returning the failure records.
+func checkGolden(
+	name string,
+	actual interface{},
+	update bool,
+	normalizers ...Normalizer) []FailureRecord {
+	defer func(old bool) { *fUpdateGolden = old }(*fUpdateGolden)
+	*fUpdateGolden = update
+
+	setUpCurrentTest()
+	ExpectMatchesGolden(name, actual, normalizers...)
+	return currentlyRunningTest.failureRecords
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestGoldenFileMissing(t *testing.T) {
+	_, restore := useTempGoldenDir(t)
+	defer restore()
+
+	records := checkGolden("missing.txt", "taco", false)
+	assertEqInt(t, 1, len(records))
+	if !strings.Contains(records[0].Error, "--ogletest.update_golden") {
+		t.Errorf("Unexpected error: %s", records[0].Error)
+	}
+
+	expectEqStr(t, "golden_test.go", records[0].FileName)
+}
+
+func TestGoldenFileUpdateAndMatch(t *testing.T) {
+	dir, restore := useTempGoldenDir(t)
+	defer restore()
+
+	records := checkGolden("sub/out.txt", []byte("foo\nbar\n"), true)
+	assertEqInt(t, 0, len(records))
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "sub", "out.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	expectEqStr(t, "foo\nbar\n", string(contents))
+
+	records = checkGolden("sub/out.txt", "foo\nbar\n", false)
+	assertEqInt(t, 0, len(records))
+}
+
+func TestGoldenFileMismatchReportsDiff(t *testing.T) {
+	dir, restore := useTempGoldenDir(t)
+	defer restore()
+
+	path := filepath.Join(dir, "out.txt")
+	if err := ioutil.WriteFile(path, []byte("foo\nbar\nbaz\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	records := checkGolden("out.txt", "foo\nqux\nbaz\n", false)
+	assertEqInt(t, 1, len(records))
+
+	err := records[0].Error
+	for _, s := range []string{"Diff (-expected +actual):", "- bar", "+ qux"} {
+		if !strings.Contains(err, s) {
+			t.Errorf("Expected %q in error: %s", s, err)
+		}
+	}
+}
+
+func TestGoldenFileNormalizers(t *testing.T) {
+	dir, restore := useTempGoldenDir(t)
+	defer restore()
+
+	actual := "/home/jacobsa/src/foo_test.go:17: took 0.034s\n" +
+		"FAIL after 12ms for user 1234\n"
+
+	records := checkGolden(
+		"out.txt",
+		actual,
+		true,
+		NormalizePaths(),
+		NormalizeTimings(),
+		ReplaceRegexp(`user \d+`, "user N"))
+
+	assertEqInt(t, 0, len(records))
+
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	expectEqStr(
+		t,
+		"/some/path/foo_test.go:17: took 1.23s\nFAIL after 1.23s for user N\n",
+		string(contents))
+}
+
+func TestGoldenFileInvalidType(t *testing.T) {
+	msg := panicMessage(func() { checkGolden("out.txt", 17, false) })
+	if !strings.Contains(msg, "expected a string or []byte, got int") {
+		t.Errorf("Unexpected panic: %q", msg)
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	expectEqStr(
+		t,
+		"Diff (-expected +actual):\n  foo\n- bar\n+ qux",
+		DiffText("foo\nbar", "foo\nqux"))
+
+	expectEqStr(
+		t,
+		"Expected: \"taco\"\nActual:   \"burrito\"",
+		DiffText("taco", "burrito"))
+}
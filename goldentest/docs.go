@@ -0,0 +1,23 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+
+// Package goldentest runs commands such as `go test` and compares their
+// normalized output against golden files. It is useful for testing test
+// frameworks and command-line tools, whose output is the thing under test.
+//
+// A typical test creates a temporary package containing a test case with
+// TempPackage, runs `go test` within it, and checks the output:
+//
+//     dir, err := goldentest.TempPackage(".", "tmp-", files)
+//     ...
+//     defer os.RemoveAll(dir)
+//
+//     goldentest.Check(t, goldentest.Case{
+//       Dir:         dir,
+//       Args:        []string{"go", "test", "-run", "TestFoo"},
+//       Normalizers: []ogletest.Normalizer{ogletest.NormalizeTimings()},
+//       GoldenFile:  "testdata/foo.golden",
+//       WantFailure: true,
+//     }, *update)
+//
+package goldentest
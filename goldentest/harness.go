@@ -0,0 +1,179 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldentest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobsa/ogletest"
+)
+
+// A Case describes a command to run and the golden file holding its expected
+// output.
+type Case struct {
+	// The directory in which to run the command. If empty, the current
+	// directory is used.
+	Dir string
+
+	// The command to run followed by its arguments, e.g.
+	// []string{"go", "test", "-v"}.
+	Args []string
+
+	// Additional environment variables for the command, of the form
+	// "KEY=value". The command otherwise inherits the current environment.
+	Env []string
+
+	// Normalizers applied in order to the command's combined standard output
+	// and standard error, typically to remove paths, timings, and other details
+	// that change from run to run.
+	Normalizers []ogletest.Normalizer
+
+	// The path of the golden file holding the expected normalized output.
+	GoldenFile string
+
+	// Whether the command is expected to fail, i.e. to exit with a non-zero
+	// code. The exact code is not checked, and isn't recorded in the golden
+	// file; for `go test` it is always 1 when tests fail, and otherwise depends
+	// on how the build or the test binary failed.
+	WantFailure bool
+}
+
+// Result is the outcome of running a case.
+type Result struct {
+	// The command's normalized combined standard output and standard error.
+	Output []byte
+
+	// The code with which the command exited.
+	ExitCode int
+}
+
+// TempPackage creates a new directory within parent, with a name beginning
+// with prefix, and writes the supplied files (keyed by name) to it. The caller
+// is responsible for removing the directory.
+//
+// parent should be within a Go module (or a GOPATH workspace), typically the
+// directory of the package being tested, so that the go command resolves the
+// new package's imports, including those of the module itself, as it does for
+// other packages there. Nothing needs to be installed first.
+func TempPackage(
+	parent string,
+	prefix string,
+	files map[string][]byte) (dir string, err error) {
+	dir, err = ioutil.TempDir(parent, prefix)
+	if err != nil {
+		err = fmt.Errorf("TempDir: %v", err)
+		return
+	}
+
+	for name, contents := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), contents, 0600)
+		if err != nil {
+			os.RemoveAll(dir)
+			err = fmt.Errorf("WriteFile: %v", err)
+			return
+		}
+	}
+
+	return
+}
+
+// Run runs the command described by the supplied case and returns its
+// normalized output and exit code. It returns an error only if the command
+// could not be run or did not exit normally; a non-zero exit code is not an
+// error.
+func Run(c Case) (r Result, err error) {
+	if len(c.Args) == 0 {
+		panic("goldentest.Run: no command given.")
+	}
+
+	cmd := exec.Command(c.Args[0], c.Args[1:]...)
+	cmd.Dir = c.Dir
+	if len(c.Env) != 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+
+	output, runErr := cmd.CombinedOutput()
+	if runErr != nil {
+		exitErr, ok := runErr.(*exec.ExitError)
+		if !ok || !exitErr.Exited() {
+			err = fmt.Errorf("Running %v: %v", c.Args, runErr)
+			return
+		}
+
+		r.ExitCode = exitErr.ExitCode()
+	}
+
+	s := string(output)
+	for _, n := range c.Normalizers {
+		s = n(s)
+	}
+
+	r.Output = []byte(s)
+	return
+}
+
+// Check runs the supplied case and reports to t if the command could not be
+// run, if it didn't fail or succeed as expected (see WantFailure), or if its
+// output doesn't match the golden file, in which case the report includes a
+// diff in the format of ogletest.DiffText. If update is true, Check writes the
+// output to the golden file instead of reporting a mismatch. Returns true iff
+// there were no problems.
+func Check(t testing.TB, c Case, update bool) (ok bool) {
+	t.Helper()
+
+	r, err := Run(c)
+	if err != nil {
+		t.Errorf("%s: %v", c.GoldenFile, err)
+		return
+	}
+
+	ok = true
+	if failed := r.ExitCode != 0; failed != c.WantFailure {
+		t.Errorf("%s: bad exit code %d", c.GoldenFile, r.ExitCode)
+		ok = false
+	}
+
+	golden, err := ioutil.ReadFile(c.GoldenFile)
+	if err == nil && string(golden) == string(r.Output) {
+		return
+	}
+
+	if update {
+		if err = ioutil.WriteFile(c.GoldenFile, r.Output, 0644); err != nil {
+			t.Errorf("%s: %v", c.GoldenFile, err)
+			ok = false
+		}
+
+		return
+	}
+
+	if err != nil {
+		t.Errorf("%s: %v", c.GoldenFile, err)
+	} else {
+		t.Errorf(
+			"Output doesn't match golden file %s:\n%s",
+			c.GoldenFile,
+			ogletest.DiffText(string(golden), string(r.Output)))
+	}
+
+	ok = false
+	return
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package goldentest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+	"github.com/jacobsa/ogletest/goldentest"
+)
+
+func TestHarness(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type HarnessTest struct {
+	dir string
+}
+
+func init() { RegisterTestSuite(&HarnessTest{}) }
+
+func (t *HarnessTest) SetUp(ti *TestInfo) {
+	var err error
+	t.dir, err = ioutil.TempDir("", "harness_test")
+	AssertEq(nil, err)
+}
+
+func (t *HarnessTest) TearDown() {
+	os.RemoveAll(t.dir)
+}
+
+// A testing.TB that records errors. Methods other than Helper and Errorf
+// panic.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+// A case that prints some unstable output and exits with code 3.
+func (t *HarnessTest) failingCase() goldentest.Case {
+	return goldentest.Case{
+		Dir:  t.dir,
+		Args: []string{"sh", "-c", "echo $FOO /usr/src/foo.go:17 took 12ms; exit 3"},
+		Env:  []string{"FOO=taco"},
+		Normalizers: []Normalizer{
+			NormalizePaths(),
+			NormalizeTimings(),
+		},
+		GoldenFile:  path.Join(t.dir, "golden.txt"),
+		WantFailure: true,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *HarnessTest) TempPackage() {
+	dir, err := goldentest.TempPackage(
+		t.dir,
+		"tmp-",
+		map[string][]byte{
+			"foo_test.go": []byte("package foo"),
+			"bar.txt":     []byte("taco"),
+		})
+
+	AssertEq(nil, err)
+	ExpectThat(path.Base(dir), HasSubstr("tmp-"))
+	ExpectEq(t.dir, path.Dir(dir))
+
+	contents, err := ioutil.ReadFile(path.Join(dir, "foo_test.go"))
+	AssertEq(nil, err)
+	ExpectEq("package foo", string(contents))
+
+	contents, err = ioutil.ReadFile(path.Join(dir, "bar.txt"))
+	AssertEq(nil, err)
+	ExpectEq("taco", string(contents))
+}
+
+func (t *HarnessTest) RunNormalizesOutput() {
+	r, err := goldentest.Run(t.failingCase())
+	AssertEq(nil, err)
+
+	ExpectEq(3, r.ExitCode)
+	ExpectEq("taco /some/path/foo.go:17 took 1.23s\n", string(r.Output))
+}
+
+func (t *HarnessTest) RunReportsMissingCommand() {
+	_, err := goldentest.Run(goldentest.Case{
+		Args: []string{path.Join(t.dir, "does_not_exist")},
+	})
+
+	ExpectThat(err, Error(HasSubstr("does_not_exist")))
+}
+
+func (t *HarnessTest) CheckUpdatesAndMatchesGoldenFile() {
+	c := t.failingCase()
+
+	// Write the golden file.
+	tb := &fakeTB{}
+	ExpectTrue(goldentest.Check(tb, c, true))
+	ExpectThat(tb.errors, ElementsAre())
+
+	contents, err := ioutil.ReadFile(c.GoldenFile)
+	AssertEq(nil, err)
+	ExpectEq("taco /some/path/foo.go:17 took 1.23s\n", string(contents))
+
+	// Now the output matches it.
+	ExpectTrue(goldentest.Check(tb, c, false))
+	ExpectThat(tb.errors, ElementsAre())
+}
+
+func (t *HarnessTest) CheckReportsMismatch() {
+	c := t.failingCase()
+	err := ioutil.WriteFile(c.GoldenFile, []byte("burrito\n"), 0644)
+	AssertEq(nil, err)
+
+	tb := &fakeTB{}
+	ExpectFalse(goldentest.Check(tb, c, false))
+	ExpectThat(
+		tb.errors,
+		ElementsAre(
+			"Output doesn't match golden file "+c.GoldenFile+":\n"+
+				"Diff (-expected +actual):\n"+
+				"- burrito\n"+
+				"+ taco /some/path/foo.go:17 took 1.23s\n"+
+				"  "))
+}
+
+func (t *HarnessTest) CheckReportsMissingGoldenFile() {
+	tb := &fakeTB{}
+	ExpectFalse(goldentest.Check(tb, t.failingCase(), false))
+	AssertEq(1, len(tb.errors))
+	ExpectThat(tb.errors[0], HasSubstr("no such file"))
+}
+
+func (t *HarnessTest) CheckReportsUnexpectedFailure() {
+	c := t.failingCase()
+	c.WantFailure = false
+
+	tb := &fakeTB{}
+	ExpectFalse(goldentest.Check(tb, c, true))
+	ExpectThat(tb.errors, ElementsAre(c.GoldenFile+": bad exit code 3"))
+}
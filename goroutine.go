@@ -0,0 +1,64 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// The names of the functions that recover the panic with which AbortTest stops
+// a test: runWithProtection, which runs test methods and hooks, and
+// runGoroutine, which runs goroutines started with TestInfo.Go.
+var abortRecoverers = map[string]bool{
+	funcName(runWithProtection):        true,
+	funcName((*TestInfo).runGoroutine): true,
+}
+
+func funcName(f interface{}) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
+
+// Return true iff it is safe for the caller to stop the test with a panic:
+// that is, if it is running within a function that recovers the panic, or on
+// a goroutine run by the testing package, where the panic is reported as
+// usual. Goroutines started with a plain go statement are neither.
+func canPanicToAbort() bool {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if abortRecoverers[frame.Function] ||
+			strings.HasPrefix(frame.Function, "testing.") {
+			return true
+		}
+
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"runtime"
+	"sync"
+)
+
+var helpersMu sync.RWMutex
+
+// The names of the functions that have called Helper.
+//
+// GUARDED_BY(helpersMu)
+var helpers = make(map[string]bool)
+
+// Helper marks the calling function as a test helper. When ogletest works out
+// the location to report for a failure from ExpectThat, AssertThat,
+// AddFailure, ExpectCall, and friends, it skips over helper functions, so
+// that the failure is attributed to the line that called the helper.
+//
+// For example:
+//
+//     func checkResponse(resp *http.Response) {
+//       ogletest.Helper()
+//       ExpectEq(200, resp.StatusCode)  // Reported at the caller's line.
+//     }
+//
+// Unlike testing.T.Helper, marking applies to every test, not just the
+// currently running one.
+func Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return
+	}
+
+	name := f.Name()
+
+	// Avoid taking the write lock on the common path where the helper has
+	// already been marked.
+	helpersMu.RLock()
+	marked := helpers[name]
+	helpersMu.RUnlock()
+
+	if !marked {
+		helpersMu.Lock()
+		helpers[name] = true
+		helpersMu.Unlock()
+	}
+}
+
+func isHelper(funcName string) bool {
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+
+	return helpers[funcName]
+}
+
+// callerFrames returns the stack of the caller of this function, from the
+// innermost frame outward, ending before the frames of the ogletest runner.
+// skip is the number of additional frames to skip, as for runtime.Caller.
+// After that, frames belonging to functions marked with Helper are skipped too,
+// unless every frame is a helper.
+func callerFrames(skip int) (frames []runtime.Frame) {
+	pcs := make([]uintptr, 128)
+	n := runtime.Callers(skip+2, pcs)
+
+	it := runtime.CallersFrames(pcs[:n])
+	for n > 0 {
+		frame, more := it.Next()
+		if isRunnerFunction(frame.Function) {
+			break
+		}
+
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+
+	// Skip leading helper frames.
+	for i, frame := range frames {
+		if !isHelper(frame.Function) {
+			frames = frames[i:]
+			break
+		}
+	}
+
+	return
+}
+
+// callerFileLine is like runtime.Caller, except that it skips over frames
+// belonging to functions marked with Helper. skip is relative to the caller
+// of this function.
+func callerFileLine(skip int) (file string, line int, ok bool) {
+	frames := callerFrames(skip + 1)
+	if len(frames) == 0 {
+		return
+	}
+
+	file, line, ok = frames[0].File, frames[0].Line, true
+	return
+}
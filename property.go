@@ -0,0 +1,191 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var fPropertyChecks = flag.Int(
+	"ogletest.property_checks",
+	100,
+	"Number of random inputs to try for each ExpectForAll call.")
+
+var fPropertySeed = flag.Int64(
+	"ogletest.property_seed",
+	0,
+	"Seed for the random inputs generated by ExpectForAll. If zero, a seed is "+
+		"chosen based on the time and reported with any failure.")
+
+// The maximum size passed to generators by ExpectForAll, reached on the last
+// check.
+const maxPropertySize = 100
+
+// The maximum number of candidates that ExpectForAll will try when shrinking a
+// failing input, to bound the time spent on pathological cases.
+const maxShrinkAttempts = 1000
+
+// ExpectForAll checks that f, a function with a single parameter, holds for
+// random values generated by g (see the ogletest.property_checks flag for how
+// many). f should use ExpectThat and friends to check its property; it fails
+// for an input if it records any failures or panics.
+//
+// If f fails for some input, ExpectForAll shrinks the input to a minimal one
+// for which f still fails, and adds a single failure record reporting that
+// input along with the failures f recorded for it and the seed of the random
+// source. Rerun with --ogletest.property_seed set to that seed to reproduce.
+// errorParts are as for ExpectThat.
+//
+// For example:
+//
+//     ExpectForAll(Arbitrary(), func(xs []int) {
+//       ExpectThat(Reverse(Reverse(xs)), DeepEquals(xs))
+//     })
+//
+func ExpectForAll(g Generator, f interface{}, errorParts ...interface{}) {
+	expectForAll(g, f, 1, errorParts)
+}
+
+// The generalized form of ExpectForAll. depth is as for expectThat. Returns
+// passed iff the property held for every input.
+func expectForAll(
+	g Generator,
+	f interface{},
+	depth int,
+	errorParts []interface{}) (passed bool) {
+	fv := reflect.ValueOf(f)
+	if fv.Kind() != reflect.Func ||
+		fv.Type().NumIn() != 1 ||
+		fv.Type().NumOut() != 0 {
+		panic(fmt.Sprintf(
+			"ExpectForAll: expected a function with one parameter, got %T",
+			f))
+	}
+
+	ti := getCurrentlyRunningTest()
+	if ti == nil {
+		panic("ExpectForAll: no test is running.")
+	}
+
+	seed := *fPropertySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	t := fv.Type().In(0)
+
+	// Run f on the supplied input, returning the failures it records.
+	check := func(v reflect.Value) []FailureRecord {
+		return ti.captureFailures(func() { fv.Call([]reflect.Value{v}) })
+	}
+
+	checks := *fPropertyChecks
+	for i := 0; i < checks; i++ {
+		size := 1 + i*maxPropertySize/checks
+		v := g.Generate(t, r, size)
+		failures := check(v)
+		if len(failures) == 0 {
+			continue
+		}
+
+		// Shrink greedily: move to the first simpler input that still fails, until
+		// there are none.
+		shrinks := 0
+		attempts := 0
+	shrinking:
+		for attempts < maxShrinkAttempts {
+			for _, c := range g.Shrink(v) {
+				attempts++
+				if cf := check(c); len(cf) != 0 {
+					v = c
+					failures = cf
+					shrinks++
+					continue shrinking
+				}
+
+				if attempts >= maxShrinkAttempts {
+					break
+				}
+			}
+
+			break
+		}
+
+		addPropertyFailure(
+			v.Interface(),
+			failures,
+			seed,
+			i+1,
+			shrinks,
+			depth+1,
+			errorParts)
+		return
+	}
+
+	passed = true
+	return
+}
+
+// Add a failure record for a property that failed for the supplied input,
+// found by the given number of checks and shrunk the given number of times.
+// depth is as for expectThat.
+func addPropertyFailure(
+	input interface{},
+	failures []FailureRecord,
+	seed int64,
+	checks int,
+	shrinks int,
+	depth int,
+	errorParts []interface{}) {
+	r := FailureRecord{
+		Kind:  FailureProperty,
+		Seed:  seed,
+		Input: input,
+	}
+
+	frames := callerFrames(depth + 1)
+	if len(frames) == 0 {
+		panic("Can't find caller")
+	}
+
+	setLocation(&r, frames)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Property failed for input: %#v\n", input)
+	fmt.Fprintf(
+		&b,
+		"(found after %s and %s; seed %d)\n",
+		pluralize(checks, "check"),
+		pluralize(shrinks, "shrink"),
+		seed)
+
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n%s:%d:\n%s\n", f.FileName, f.LineNumber, f.Error)
+	}
+
+	if len(errorParts) != 0 {
+		fmt.Fprintf(&b, "\n%s", formatErrorParts("ExpectForAll", errorParts))
+	}
+
+	r.Error = strings.TrimRight(b.String(), "\n")
+	AddFailureRecord(r)
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type propertyTestStruct struct {
+	A int
+	B string
+	C []uint8
+	D map[string]bool
+	E *float64
+	F [2]bool
+
+	unexported int
+}
+
+// Check the supplied property with a fixed seed in a fresh test, returning the
+// failure records.
+func checkProperty(f interface{}) []FailureRecord {
+	defer func(old int64) { *fPropertySeed = old }(*fPropertySeed)
+	*fPropertySeed = 17
+
+	setUpCurrentTest()
+	ExpectForAll(Arbitrary(), f)
+	return currentlyRunningTest.failureRecords
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestArbitraryGeneratesAndShrinksStructs(t *testing.T) {
+	g := Arbitrary()
+	typ := reflect.TypeOf(propertyTestStruct{})
+	r := rand.New(rand.NewSource(17))
+
+	for i := 0; i < 100; i++ {
+		v := g.Generate(typ, r, 10)
+		if v.Type() != typ {
+			t.Fatalf("Wrong type: %v", v.Type())
+		}
+
+		for _, c := range g.Shrink(v) {
+			if c.Type() != typ {
+				t.Fatalf("Wrong shrunk type: %v", c.Type())
+			}
+		}
+	}
+}
+
+func TestPassingProperty(t *testing.T) {
+	records := checkProperty(func(xs []int) {
+		ExpectEq(len(xs), len(append([]int{}, xs...)))
+	})
+
+	assertEqInt(t, 0, len(records))
+}
+
+func TestShrinksInts(t *testing.T) {
+	records := checkProperty(func(x int) {
+		ExpectLt(x, 10)
+	})
+
+	assertEqInt(t, 1, len(records))
+	r := records[0]
+	expectEqStr(t, "property", r.Kind.String())
+	expectEqInt(t, 17, int(r.Seed))
+	expectEqInt(t, 10, r.Input.(int))
+}
+
+func TestShrinksSlices(t *testing.T) {
+	records := checkProperty(func(xs []int) {
+		for _, x := range xs {
+			AssertLt(x, 5)
+		}
+	})
+
+	assertEqInt(t, 1, len(records))
+	if !reflect.DeepEqual([]int{5}, records[0].Input) {
+		t.Errorf("Unexpected input: %#v", records[0].Input)
+	}
+}
+
+func TestShrinksStructFields(t *testing.T) {
+	records := checkProperty(func(s propertyTestStruct) {
+		ExpectLt(len(s.B), 2)
+	})
+
+	assertEqInt(t, 1, len(records))
+	s := records[0].Input.(propertyTestStruct)
+	expectEqInt(t, 2, len(s.B))
+	expectEqInt(t, 0, s.A)
+	expectEqInt(t, 0, len(s.C))
+	expectEqInt(t, 0, len(s.D))
+	if s.E != nil {
+		t.Errorf("Expected nil E, got %v", *s.E)
+	}
+}
+
+func TestPanickingProperty(t *testing.T) {
+	records := checkProperty(func(xs []string) {
+		if len(xs) > 2 {
+			panic("too long")
+		}
+	})
+
+	assertEqInt(t, 1, len(records))
+	expectEqInt(t, 3, len(records[0].Input.([]string)))
+}
@@ -15,6 +15,11 @@
 
 package ogletest
 
+import (
+	"reflect"
+	"testing"
+)
+
 // The input to ogletest.Register. Most users will want to use
 // ogletest.RegisterTestSuite.
 //
@@ -32,6 +37,14 @@ type TestSuite struct {
 	// The test functions comprising this suite.
 	TestFunctions []TestFunction
 
+	// The benchmark functions comprising this suite, which are run by
+	// RunBenchmarks rather than RunTests.
+	Benchmarks []BenchmarkFunction
+
+	// The fuzz targets comprising this suite, which are run by RunFuzzTarget
+	// rather than RunTests.
+	FuzzTargets []FuzzTarget
+
 	// If non-nil, a function that will be run exactly once, after all of the
 	// test functions have run.
 	TearDown func()
@@ -55,6 +68,49 @@ type TestFunction struct {
 	TearDown func()
 }
 
+type BenchmarkFunction struct {
+	// The name of this benchmark function, relative to the suite in which it
+	// resides. If the name is "Frobnicate", then the benchmark might be
+	// presented by `go test -bench` as "BenchmarkOgletest/FooTest/Frobnicate".
+	Name string
+
+	// If non-nil, a function that is run before each call to Run, passed a
+	// pointer to a struct containing information about the benchmark run. Its
+	// time is not included in the benchmark's timing.
+	SetUp func(*TestInfo)
+
+	// The function to invoke for the benchmark body, which should run the
+	// benchmarked code b.N times. Must be non-nil. Will not be run if SetUp
+	// panics.
+	Run func(b *testing.B)
+
+	// If non-nil, a function that is run after each call to Run. Its time is not
+	// included in the benchmark's timing.
+	TearDown func()
+}
+
+type FuzzTarget struct {
+	// The name of this fuzz target, relative to the suite in which it resides.
+	// If the name is "Parse", then the target is selected by passing
+	// "FooTest.Parse" to RunFuzzTarget.
+	Name string
+
+	// A function taking the fuzzed arguments, e.g. func(s string, n int). The
+	// argument types must be supported by testing.F. Must be non-nil.
+	Run interface{}
+
+	// The seed corpus: lists of arguments for Run, whose types must match its
+	// parameters exactly.
+	Seeds [][]interface{}
+
+	// If non-nil, a function that is run before each call to Run, passed a
+	// pointer to a struct containing information about the run.
+	SetUp func(*TestInfo)
+
+	// If non-nil, a function that is run after each call to Run.
+	TearDown func()
+}
+
 // Register a test suite for execution by RunTests.
 //
 // This is the most general registration mechanism. Most users will want
@@ -63,7 +119,14 @@ type TestFunction struct {
 //
 // Panics on invalid input.
 func Register(suite TestSuite) {
-	// Make sure the suite is legal.
+	checkTestSuite(suite)
+
+	// Save the suite for later.
+	registeredSuites = append(registeredSuites, suite)
+}
+
+// Panic if the supplied suite is not legal.
+func checkTestSuite(suite TestSuite) {
 	if suite.Name == "" {
 		panic("Test suites must have names.")
 	}
@@ -78,8 +141,25 @@ func Register(suite TestSuite) {
 		}
 	}
 
-	// Save the suite for later.
-	registeredSuites = append(registeredSuites, suite)
+	for _, bf := range suite.Benchmarks {
+		if bf.Name == "" {
+			panic("Benchmark functions must have names.")
+		}
+
+		if bf.Run == nil {
+			panic("Benchmark functions must have non-nil run fields.")
+		}
+	}
+
+	for _, ft := range suite.FuzzTargets {
+		if ft.Name == "" {
+			panic("Fuzz targets must have names.")
+		}
+
+		if ft.Run == nil || reflect.TypeOf(ft.Run).Kind() != reflect.Func {
+			panic("Fuzz targets must have function run fields.")
+		}
+	}
 }
 
 // The list of test suites previously registered.
@@ -17,9 +17,15 @@ package ogletest
 
 import (
 	"fmt"
+	"path"
 	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
 
 	"github.com/jacobsa/ogletest/srcutil"
+	"golang.org/x/net/context"
 )
 
 // Test suites that implement this interface have special meaning to
@@ -27,8 +33,10 @@ import (
 type SetUpTestSuiteInterface interface {
 	// This method will be called exactly once, before the first test method is
 	// run. The receiver of this method will be a zero value of the test suite
-	// type, and is not shared with any other methods. Use this method to set up
-	// any necessary global state shared by all of the test methods.
+	// type (or, with RegisterTestSuiteFunc, a fresh value returned by the
+	// factory), and is not shared with any other methods. Use this method to set
+	// up any necessary state shared by all of the test methods, storing it in
+	// fields tagged `ogletest:"shared"` (see RegisterTestSuite) or in globals.
 	SetUpTestSuite()
 }
 
@@ -37,8 +45,10 @@ type SetUpTestSuiteInterface interface {
 type TearDownTestSuiteInterface interface {
 	// This method will be called exactly once, after the last test method is
 	// run. The receiver of this method will be a zero value of the test suite
-	// type, and is not shared with any other methods. Use this method to clean
-	// up after any necessary global state shared by all of the test methods.
+	// type (or, with RegisterTestSuiteFunc, a fresh value returned by the
+	// factory), and is not shared with any other methods. Use this method to
+	// clean up after any necessary global state shared by all of the test
+	// methods.
 	TearDownTestSuite()
 }
 
@@ -61,6 +71,15 @@ type TearDownInterface interface {
 	TearDown()
 }
 
+// Test suites that implement this interface have special meaning to
+// RegisterTestSuite.
+type NonTestMethodsInterface interface {
+	// Return the names of exported methods that are helpers rather than tests,
+	// and so should not be run. This method is called on a zero value of the
+	// test suite type when the suite is registered.
+	NonTestMethods() []string
+}
+
 // RegisterTestSuite tells ogletest about a test suite containing tests that it
 // should run. Any exported method on the type pointed to by the supplied
 // prototype value will be treated as test methods, with the exception of the
@@ -71,10 +90,98 @@ type TearDownInterface interface {
 //  *  SetUpInterface
 //  *  TearDownInterface
 //  *  TearDownTestSuiteInterface
+//  *  NonTestMethodsInterface
+//  *  FuzzSeedsInterface
+//
+// Test methods may have any of the following signatures. A method that takes a
+// *TestInfo or context.Context is passed the running test's TestInfo or its
+// Ctx field. A non-nil error returned by a test method is recorded as a
+// failure.
+//
+//     func (t *FooTest) Bar()
+//     func (t *FooTest) Bar(ti *ogletest.TestInfo)
+//     func (t *FooTest) Bar(ctx context.Context)
+//     func (t *FooTest) Bar() error
+//     func (t *FooTest) Bar(ti *ogletest.TestInfo) error
+//     func (t *FooTest) Bar(ctx context.Context) error
+//
+// Methods with the signature func(*testing.B) are benchmark methods rather than
+// test methods, and must have names starting with "Benchmark". They are run by
+// RunBenchmarks rather than RunTests:
+//
+//     func (t *FooTest) BenchmarkParse(b *testing.B) {
+//       for i := 0; i < b.N; i++ {
+//         Parse(t.input)
+//       }
+//     }
+//
+// Similarly, methods whose names start with "Fuzz" and that take only arguments
+// of types supported by testing.F are fuzz targets, run by RunFuzzTarget. Their
+// seed corpora are given by FuzzSeedsInterface.
+//
+// RegisterTestSuite panics, listing every problem it finds, if any exported
+// method other than those listed by NonTestMethods has a different signature,
+// or if a special method such as SetUp has the wrong signature (which would
+// otherwise cause it to be silently ignored).
 //
 // Each test method is invoked on a different receiver, which is initially a
 // zero value of the test suite type.
 //
+// A suite may embed (by value) other exported struct types to inherit their
+// fixtures and tests. Inherited test methods run after the suite's own, and
+// are named after the type declaring them, e.g. "FooTest.BaseSuite.SomeTest".
+// Exclude unwanted inherited tests by listing them in NonTestMethods.
+//
+// The hooks SetUp, TearDown, SetUpTestSuite, and TearDownTestSuite of an
+// embedded type are promoted as usual if the suite doesn't declare its own. If
+// it does, the embedded field must say what should happen to the embedded
+// type's hooks, and RegisterTestSuite panics if it doesn't. To have ogletest
+// call the hooks of the embedded type along with those of the suite, tag the
+// field `ogletest:"chain"`:
+//
+//     type FooTest struct {
+//       BaseSuite `ogletest:"chain"`
+//     }
+//
+// The SetUpTestSuite and SetUp methods of a chained type then run before those
+// of the types embedding it, and TearDown and TearDownTestSuite run in the
+// reverse order. Chaining applies recursively to types embedded with the tag
+// within chained types.
+//
+// Alternatively, tag the field `ogletest:"nochain"` to follow the usual Go
+// rules, under which the suite's hooks replace the embedded ones and may call
+// them explicitly:
+//
+//     type FooTest struct {
+//       BaseSuite `ogletest:"nochain"`
+//     }
+//
+//     func (t *FooTest) SetUp(ti *ogletest.TestInfo) {
+//       t.BaseSuite.SetUp(ti)
+//       ...
+//     }
+//
+// The exception is fields tagged `ogletest:"shared"`, which must be exported.
+// Their values are copied from the SetUpTestSuite receiver into each test's
+// receiver (before SetUp) and into the TearDownTestSuite receiver. Use them
+// for expensive fixtures that should be built once per suite run:
+//
+//     type DatabaseTest struct {
+//       DB *fakedb.DB `ogletest:"shared"`
+//     }
+//
+//     func (t *DatabaseTest) SetUpTestSuite() {
+//       t.DB = fakedb.Start()
+//     }
+//
+//     func (t *DatabaseTest) TearDownTestSuite() {
+//       t.DB.Stop()
+//     }
+//
+// Note that shared fields are copied by value, so tests see the same
+// pointers, maps, and slices, but assigning to a shared field in one test does
+// not affect the others.
+//
 // Example:
 //
 //     // Some value that is needed by the tests but is expensive to compute.
@@ -105,87 +212,538 @@ type TearDownInterface interface {
 //     }
 //
 func RegisterTestSuite(p interface{}) {
+	Register(makeTestSuite("RegisterTestSuite", p))
+}
+
+// RegisterTestSuiteFunc is like RegisterTestSuite, but rather than starting
+// each test with a zero value of the test suite type it calls the supplied
+// factory function, which must have a signature like
+//
+//     func() *FooTest
+//
+// The test methods are those of the type returned by the factory, as described
+// for RegisterTestSuite. The factory is called once for each test method (just
+// before SetUp), and once each for SetUpTestSuite and TearDownTestSuite if
+// present. It must not return nil.
+//
+// This is useful for suites that need shared configuration or injected fakes
+// wired up before SetUp runs:
+//
+//     func newFooTest() *FooTest {
+//       return &FooTest{clock: fakeClock}
+//     }
+//
+//     func init() { ogletest.RegisterTestSuiteFunc(newFooTest) }
+//
+func RegisterTestSuiteFunc(f interface{}) {
+	Register(makeTestSuiteFromFunc("RegisterTestSuiteFunc", f))
+}
+
+// Transform the supplied prototype value into a TestSuite, as described for
+// RegisterTestSuite. caller is the name of the exported function on whose
+// behalf this is being done, for use in panic messages.
+func makeTestSuite(caller string, p interface{}) (suite TestSuite) {
 	if p == nil {
-		panic("RegisterTestSuite called with nil suite.")
+		panic(caller + " called with nil suite.")
+	}
+
+	typ := reflect.TypeOf(p)
+	if typ.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("%s called with non-pointer suite: %v", caller, typ))
 	}
 
-	val := reflect.ValueOf(p)
-	typ := val.Type()
-	var zeroInstance reflect.Value
+	return makeTestSuiteWithFactory(
+		caller,
+		typ,
+		func() reflect.Value { return reflect.New(typ.Elem()) })
+}
+
+// Transform the supplied factory function into a TestSuite, as described for
+// RegisterTestSuiteFunc.
+func makeTestSuiteFromFunc(caller string, f interface{}) (suite TestSuite) {
+	if f == nil {
+		panic(caller + " called with nil function.")
+	}
+
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func ||
+		ft.NumIn() != 0 ||
+		ft.NumOut() != 1 ||
+		ft.Out(0).Kind() != reflect.Ptr {
+		panic(fmt.Sprintf(
+			"%s: expected a function like func() *FooTest, got %v",
+			caller,
+			ft))
+	}
+
+	typ := ft.Out(0)
+	newInstance := func() (instance reflect.Value) {
+		instance = fv.Call(nil)[0]
+		if instance.IsNil() {
+			panic(fmt.Sprintf("%s: factory for %v returned nil.", caller, typ))
+		}
 
+		return
+	}
+
+	return makeTestSuiteWithFactory(caller, typ, newInstance)
+}
+
+// Build a TestSuite for the pointer type typ, obtaining receivers from
+// newInstance.
+func makeTestSuiteWithFactory(
+	caller string,
+	typ reflect.Type,
+	newInstance func() reflect.Value) (suite TestSuite) {
 	// We will transform to a TestSuite struct.
-	suite := TestSuite{}
 	suite.Name = typ.Elem().Name()
 
-	zeroInstance = reflect.New(typ.Elem())
-	if i, ok := zeroInstance.Interface().(SetUpTestSuiteInterface); ok {
-		suite.SetUp = func() { i.SetUpTestSuite() }
+	// The receiver for SetUpTestSuite, whose shared fields are copied into every
+	// other instance. It is created afresh each time the suite runs.
+	shared := sharedFields(caller, typ)
+	var suiteInstance reflect.Value
+
+	levels := suiteLevels(typ)
+
+	if hasHook(levels, "SetUpTestSuite") || len(shared) != 0 {
+		suite.SetUp = func() {
+			suiteInstance = reflect.Value{}
+			suiteInstance = newInstance()
+			callHooks(suiteInstance, levels, "SetUpTestSuite", false, func(r interface{}) {
+				r.(SetUpTestSuiteInterface).SetUpTestSuite()
+			})
+		}
 	}
 
-	zeroInstance = reflect.New(typ.Elem())
-	if i, ok := zeroInstance.Interface().(TearDownTestSuiteInterface); ok {
-		suite.TearDown = func() { i.TearDownTestSuite() }
+	if hasHook(levels, "TearDownTestSuite") {
+		suite.TearDown = func() {
+			instance := newInstance()
+			copySharedFields(suiteInstance, instance, shared)
+			callHooks(instance, levels, "TearDownTestSuite", true, func(r interface{}) {
+				r.(TearDownTestSuiteInterface).TearDownTestSuite()
+			})
+		}
 	}
 
-	// Transform a list of test methods for the suite, filtering them to just the
-	// ones that we don't need to skip.
-	for _, method := range filterMethods(suite.Name, srcutil.GetMethodsInSourceOrder(typ)) {
-		var tf TestFunction
-		tf.Name = method.Name
+	// Return SetUp and TearDown functions for a test or benchmark method, along
+	// with the fixture on which the method should operate. SetUp fills in the
+	// fixture afresh each time the method runs, so that user factories aren't
+	// called at registration time and repeated runs don't share state.
+	// tearDown is nil if there is nothing to do.
+	newFixture := func() (f *methodFixture, setUp func(*TestInfo), tearDown func()) {
+		f = new(methodFixture)
+		setUp = func(ti *TestInfo) {
+			f.ti = ti
+			f.instance = reflect.Value{}
+			f.instance = newInstance()
+			copySharedFields(suiteInstance, f.instance, shared)
+			callHooks(f.instance, levels, "SetUp", false, func(r interface{}) {
+				r.(SetUpInterface).SetUp(ti)
+			})
+		}
 
-		// Create an instance to be operated on by all of the TestFunction's
-		// internal functions.
-		instance := reflect.New(typ.Elem())
+		if hasHook(levels, "TearDown") {
+			tearDown = func() {
+				// Skip if the factory itself panicked.
+				if !f.instance.IsValid() {
+					return
+				}
 
-		// Bind the functions to the instance.
-		if i, ok := instance.Interface().(SetUpInterface); ok {
-			tf.SetUp = func(ti *TestInfo) { i.SetUp(ti) }
+				callHooks(f.instance, levels, "TearDown", true, func(r interface{}) {
+					r.(TearDownInterface).TearDown()
+				})
+			}
 		}
 
-		methodCopy := method
-		tf.Run = func() { runTestMethod(instance, methodCopy) }
+		return
+	}
 
-		if i, ok := instance.Interface().(TearDownInterface); ok {
-			tf.TearDown = func() { i.TearDown() }
-		}
+	// Transform the lists of test and benchmark methods for the suite,
+	// validating them all first.
+	methods := getSuiteMethods(caller, suite.Name, typ, levels)
+	for _, method := range methods.tests {
+		var tf TestFunction
+		tf.Name = inheritedPrefix(levels, method.Name) + method.Name
+
+		f, setUp, tearDown := newFixture()
+		methodCopy := method
+		tf.SetUp = setUp
+		tf.Run = func() { runTestMethod(f.instance, methodCopy, f.ti) }
+		tf.TearDown = tearDown
 
 		// Save the TestFunction.
 		suite.TestFunctions = append(suite.TestFunctions, tf)
 	}
 
-	// Register the suite.
-	Register(suite)
+	for _, method := range methods.benchmarks {
+		var bf BenchmarkFunction
+		bf.Name = inheritedPrefix(levels, method.Name) +
+			strings.TrimPrefix(method.Name, "Benchmark")
+
+		f, setUp, tearDown := newFixture()
+		methodCopy := method
+		bf.SetUp = setUp
+		bf.Run = func(b *testing.B) { runBenchmarkMethod(f.instance, methodCopy, b) }
+		bf.TearDown = tearDown
+
+		suite.Benchmarks = append(suite.Benchmarks, bf)
+	}
+
+	for _, method := range methods.fuzzTargets {
+		var ft FuzzTarget
+		ft.Name = inheritedPrefix(levels, method.Name) +
+			strings.TrimPrefix(method.Name, "Fuzz")
+
+		f, setUp, tearDown := newFixture()
+		ft.Run = makeFuzzFunction(method, func() reflect.Value { return f.instance })
+		ft.Seeds = methods.fuzzSeeds[method.Name]
+		ft.SetUp = setUp
+		ft.TearDown = tearDown
+
+		suite.FuzzTargets = append(suite.FuzzTargets, ft)
+	}
+
+	return
 }
 
-func runTestMethod(suite reflect.Value, method reflect.Method) {
-	if method.Func.Type().NumIn() != 1 {
-		panic(fmt.Sprintf(
-			"%s: expected 1 args, actually %d.",
-			method.Name,
-			method.Func.Type().NumIn()))
+// The receiver and TestInfo for a run of a test or benchmark method.
+type methodFixture struct {
+	instance reflect.Value
+	ti       *TestInfo
+}
+
+// Return the indices of the fields of the struct type pointed to by typ that
+// are tagged `ogletest:"shared"`, panicking if any such field is unexported or
+// the tag has an unknown value.
+func sharedFields(caller string, typ reflect.Type) (indices []int) {
+	elem := typ.Elem()
+	if elem.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+		tag, ok := field.Tag.Lookup("ogletest")
+		if !ok {
+			continue
+		}
+
+		if tag == chainTag || tag == noChainTag {
+			if !field.Anonymous || field.Type.Kind() != reflect.Struct {
+				panic(fmt.Sprintf(
+					"%s: %s.%s is tagged %q but isn't an embedded struct.",
+					caller,
+					elem.Name(),
+					field.Name,
+					tag))
+			}
+
+			continue
+		}
+
+		if tag != "shared" {
+			panic(fmt.Sprintf(
+				"%s: %s.%s has unknown ogletest tag %q.",
+				caller,
+				elem.Name(),
+				field.Name,
+				tag))
+		}
+
+		if field.PkgPath != "" {
+			panic(fmt.Sprintf(
+				"%s: shared field %s.%s must be exported.",
+				caller,
+				elem.Name(),
+				field.Name))
+		}
+
+		indices = append(indices, i)
+	}
+
+	return
+}
+
+// Copy the shared fields from one suite instance to another. Does nothing if
+// from is the zero Value (i.e. the suite has not been set up).
+func copySharedFields(from reflect.Value, to reflect.Value, indices []int) {
+	if !from.IsValid() {
+		return
+	}
+
+	for _, i := range indices {
+		to.Elem().Field(i).Set(from.Elem().Field(i))
+	}
+}
+
+var (
+	testInfoType  = reflect.TypeOf((*TestInfo)(nil))
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	benchmarkType = reflect.TypeOf((*testing.B)(nil))
+)
+
+// The signatures expected of the special methods, as described by the
+// interfaces above.
+var specialMethodSignatures = map[string]string{
+	"SetUpTestSuite":    "func()",
+	"TearDownTestSuite": "func()",
+	"SetUp":             "func(*ogletest.TestInfo)",
+	"TearDown":          "func()",
+	"NonTestMethods":    "func() []string",
+	"FuzzSeeds":         "func() map[string][][]interface {}",
+}
+
+// The special methods that are chained across embedded suites tagged
+// `ogletest:"chain"`.
+var chainedMethods = []string{
+	"SetUpTestSuite",
+	"SetUp",
+	"TearDown",
+	"TearDownTestSuite",
+}
+
+// Return a description of the problem with the supplied test method's
+// signature, or the empty string if it is one accepted by RegisterTestSuite.
+func checkTestMethod(method reflect.Method) string {
+	t := method.Type
+	ok := t.NumIn() <= 2 && t.NumOut() <= 1
+	if ok && t.NumIn() == 2 {
+		ok = t.In(1) == testInfoType || t.In(1) == contextType
+	}
+
+	if ok && t.NumOut() == 1 {
+		ok = t.Out(0) == errorType
+	}
+
+	if ok {
+		return ""
 	}
 
-	method.Func.Call([]reflect.Value{suite})
+	return fmt.Sprintf(
+		"%s has signature %s; test methods must take no arguments, a "+
+			"*TestInfo, or a context.Context, and return nothing or an error",
+		method.Name,
+		methodSignature(method))
 }
 
-func filterMethods(suiteName string, in []reflect.Method) (out []reflect.Method) {
-	for _, m := range in {
-		// Skip set up, tear down, and unexported methods.
-		if isSpecialMethod(m.Name) || !isExportedMethod(m.Name) {
+// The methods of a test suite type, sorted by kind.
+type suiteMethods struct {
+	tests       []reflect.Method
+	benchmarks  []reflect.Method
+	fuzzTargets []reflect.Method
+
+	// Seed corpora for the fuzz targets, by method name, as returned by
+	// FuzzSeeds.
+	fuzzSeeds map[string][][]interface{}
+}
+
+// Return the test, benchmark, and fuzz methods of the supplied suite pointer
+// type in source order, panicking with a description of every problem found
+// if any of its exported methods is neither one of those, a special method
+// with the expected signature, nor listed by NonTestMethods.
+func getSuiteMethods(
+	caller string,
+	suiteName string,
+	typ reflect.Type,
+	levels []suiteLevel) (methods suiteMethods) {
+	var problems []string
+
+	// Check for hooks that silently hide embedded ones.
+	for _, l := range levels {
+		problems = append(problems, l.hiddenHookProblems()...)
+	}
+
+	// Check the hooks declared by chained embedded suites, which are called
+	// directly rather than through the suite type.
+	for _, l := range levels[:len(levels)-1] {
+		for _, name := range chainedMethods {
+			m, ok := l.typ.MethodByName(name)
+			if !ok || !l.declaresHook(name) {
+				continue
+			}
+
+			expected := specialMethodSignatures[name]
+
+			if sig := methodSignature(m); sig != expected {
+				problems = append(
+					problems,
+					fmt.Sprintf(
+						"%s.%s has signature %s; expected %s",
+						l.typ.Elem().Name(),
+						name,
+						sig,
+						expected))
+			}
+		}
+	}
+
+	// Find the methods the user has told us aren't tests.
+	nonTest := make(map[string]bool)
+	if i, ok := reflect.New(typ.Elem()).Interface().(NonTestMethodsInterface); ok {
+		for _, name := range i.NonTestMethods() {
+			if _, ok := typ.MethodByName(name); !ok {
+				problems = append(
+					problems,
+					fmt.Sprintf("NonTestMethods lists unknown method %s", name))
+			}
+
+			nonTest[name] = true
+		}
+	}
+
+	for _, m := range srcutil.GetMethodsInSourceOrder(typ) {
+		switch {
+		case !isExportedMethod(m.Name) || nonTest[m.Name]:
 			continue
+
+		case isSpecialMethod(m.Name):
+			// Hooks inherited from chained embedded suites were checked above.
+			if !levels[len(levels)-1].declaresHook(m.Name) {
+				continue
+			}
+
+			if sig := methodSignature(m); sig != specialMethodSignatures[m.Name] {
+				problems = append(
+					problems,
+					fmt.Sprintf(
+						"%s has signature %s; expected %s",
+						m.Name,
+						sig,
+						specialMethodSignatures[m.Name]))
+			}
+
+		case isBenchmarkMethod(m):
+			if !strings.HasPrefix(m.Name, "Benchmark") || m.Name == "Benchmark" {
+				problems = append(
+					problems,
+					fmt.Sprintf(
+						"%s takes a *testing.B, but its name doesn't start with "+
+							"Benchmark",
+						m.Name))
+				continue
+			}
+
+			methods.benchmarks = append(methods.benchmarks, m)
+
+		case isFuzzMethod(m):
+			methods.fuzzTargets = append(methods.fuzzTargets, m)
+
+		case looksLikeFuzzMethod(m):
+			problems = append(problems, fuzzMethodProblem(m))
+
+		default:
+			if problem := checkTestMethod(m); problem != "" {
+				problems = append(problems, problem)
+				continue
+			}
+
+			methods.tests = append(methods.tests, m)
 		}
+	}
+
+	// Check the seed corpora for fuzz targets.
+	if i, ok := reflect.New(typ.Elem()).Interface().(FuzzSeedsInterface); ok {
+		methods.fuzzSeeds = i.FuzzSeeds()
+		problems = append(
+			problems,
+			checkFuzzSeeds(methods.fuzzTargets, methods.fuzzSeeds)...)
+	}
 
-		out = append(out, m)
+	// Run the suite's own test methods before inherited ones, and those
+	// inherited from shallower levels first.
+	sortByLevel(levels, methods.tests)
+	sortByLevel(levels, methods.benchmarks)
+	sortByLevel(levels, methods.fuzzTargets)
+
+	if len(problems) != 0 {
+		panic(fmt.Sprintf(
+			"%s: invalid methods in %s:\n\t%s",
+			caller,
+			suiteName,
+			strings.Join(problems, "\n\t")))
 	}
 
 	return
 }
 
+// Call the supplied benchmark method, which must have been accepted by
+// isBenchmarkMethod, on the supplied suite instance.
+func runBenchmarkMethod(suite reflect.Value, method reflect.Method, b *testing.B) {
+	method.Func.Call([]reflect.Value{suite, reflect.ValueOf(b)})
+}
+
+// Sort the supplied methods stably by the depth of the level declaring them.
+func sortByLevel(levels []suiteLevel, methods []reflect.Method) {
+	sort.SliceStable(methods, func(i, j int) bool {
+		return len(declaringLevel(levels, methods[i].Name).index) <
+			len(declaringLevel(levels, methods[j].Name).index)
+	})
+}
+
+// Is the supplied method a benchmark method, i.e. does it have the signature
+// func(*testing.B)?
+func isBenchmarkMethod(method reflect.Method) bool {
+	t := method.Type
+	return t.NumIn() == 2 && t.In(1) == benchmarkType && t.NumOut() == 0
+}
+
+// Return the type of the supplied method without its receiver, e.g.
+// "func(int) string".
+func methodSignature(method reflect.Method) string {
+	t := method.Type
+	var in, out []reflect.Type
+	for i := 1; i < t.NumIn(); i++ {
+		in = append(in, t.In(i))
+	}
+
+	for i := 0; i < t.NumOut(); i++ {
+		out = append(out, t.Out(i))
+	}
+
+	return reflect.FuncOf(in, out, t.IsVariadic()).String()
+}
+
+// Call the supplied test method, which must have been accepted by
+// checkTestMethod, on the supplied suite instance. If it returns a non-nil
+// error, record a failure.
+func runTestMethod(suite reflect.Value, method reflect.Method, ti *TestInfo) {
+	args := []reflect.Value{suite}
+	if method.Type.NumIn() == 2 {
+		if method.Type.In(1) == testInfoType {
+			args = append(args, reflect.ValueOf(ti))
+		} else {
+			args = append(args, reflect.ValueOf(&ti.Ctx).Elem())
+		}
+	}
+
+	results := method.Func.Call(args)
+	if len(results) == 0 || results[0].IsNil() {
+		return
+	}
+
+	r := FailureRecord{
+		Error: fmt.Sprintf("Returned error: %v", results[0].Interface()),
+	}
+
+	// Attribute the failure to the method's declaration.
+	if f := runtime.FuncForPC(method.Func.Pointer()); f != nil {
+		r.FilePath, r.LineNumber = f.FileLine(f.Entry())
+		r.FileName = path.Base(r.FilePath)
+		r.FunctionName = f.Name()
+	}
+
+	AddFailureRecord(r)
+}
+
 func isSpecialMethod(name string) bool {
 	return (name == "SetUpTestSuite") ||
 		(name == "TearDownTestSuite") ||
 		(name == "SetUp") ||
-		(name == "TearDown")
+		(name == "TearDown") ||
+		(name == "NonTestMethods") ||
+		(name == "FuzzSeeds")
 }
 
 func isExportedMethod(name string) bool {
@@ -0,0 +1,151 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// Call f, returning the value it panics with, formatted with %v, or the empty
+// string if it doesn't panic.
+func panicMessage(f func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+
+	f()
+	return
+}
+
+type badSignatureTest struct{}
+
+func (t *badSignatureTest) SetUp()            {}
+func (t *badSignatureTest) TakesInt(int)      {}
+func (t *badSignatureTest) ReturnsInt() int   { return 0 }
+func (t *badSignatureTest) unexported(string) {}
+func (t *badSignatureTest) Passes()           {}
+
+type nonTestMethodsTest struct{}
+
+func (t *nonTestMethodsTest) NonTestMethods() []string {
+	return []string{"Helper", "OtherHelper"}
+}
+
+func (t *nonTestMethodsTest) Helper()               {}
+func (t *nonTestMethodsTest) OtherHelper(int) error { return nil }
+func (t *nonTestMethodsTest) SomeTest()             {}
+
+type unknownNonTestMethodTest struct{}
+
+func (t *unknownNonTestMethodTest) NonTestMethods() []string {
+	return []string{"Helpr"}
+}
+
+func (t *unknownNonTestMethodTest) Helper() {}
+
+// An embedded suite whose SetUp is hidden by the suites embedding it.
+type HiddenHookBase struct{}
+
+func (t *HiddenHookBase) SetUp(ti *TestInfo) {}
+
+type hiddenHookTest struct {
+	HiddenHookBase
+}
+
+func (t *hiddenHookTest) SetUp(ti *TestInfo) {}
+
+type explicitHookTest struct {
+	HiddenHookBase `ogletest:"nochain"`
+}
+
+func (t *explicitHookTest) SetUp(ti *TestInfo) {
+	t.HiddenHookBase.SetUp(ti)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestBadTestMethodSignatures(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &badSignatureTest{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in badSignatureTest:\n"+
+			"\tSetUp has signature func(); expected func(*ogletest.TestInfo)\n"+
+			"\tTakesInt has signature func(int); test methods must take no "+
+			"arguments, a *TestInfo, or a context.Context, and return nothing or "+
+			"an error\n"+
+			"\tReturnsInt has signature func() int; test methods must take no "+
+			"arguments, a *TestInfo, or a context.Context, and return nothing or "+
+			"an error",
+		msg)
+}
+
+func TestNonTestMethods(t *testing.T) {
+	suite := makeTestSuite("RegisterTestSuite", &nonTestMethodsTest{})
+
+	var names []string
+	for _, tf := range suite.TestFunctions {
+		names = append(names, tf.Name)
+	}
+
+	expectEqStr(t, "[SomeTest]", fmt.Sprint(names))
+}
+
+func TestUnknownNonTestMethod(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &unknownNonTestMethodTest{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in unknownNonTestMethodTest:\n"+
+			"\tNonTestMethods lists unknown method Helpr",
+		msg)
+}
+
+func TestRunSuiteChecksSuite(t *testing.T) {
+	msg := panicMessage(func() { RunSuite(t, &struct{}{}) })
+	expectEqStr(t, "Test suites must have names.", msg)
+}
+
+func TestHiddenEmbeddedHook(t *testing.T) {
+	msg := panicMessage(func() {
+		makeTestSuite("RegisterTestSuite", &hiddenHookTest{})
+	})
+
+	expectEqStr(
+		t,
+		"RegisterTestSuite: invalid methods in hiddenHookTest:\n"+
+			"\thiddenHookTest.SetUp hides HiddenHookBase.SetUp; tag the embedded "+
+			"field `ogletest:\"chain\"` to call both, or `ogletest:\"nochain\"` "+
+			"if hiddenHookTest.SetUp calls it itself",
+		msg)
+
+	// With the tag saying so, the suite calls the embedded hook itself.
+	suite := makeTestSuite("RegisterTestSuite", &explicitHookTest{})
+	assertEqInt(t, 0, len(suite.TestFunctions))
+}
@@ -23,6 +23,7 @@ import (
 	"path"
 	"regexp"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -41,24 +42,28 @@ var fStopEarly = flag.Bool(
 	false,
 	"If true, stop after the first failure.")
 
-// runTestsOnce protects RunTests from executing multiple times.
-var runTestsOnce sync.Once
+// runMu serializes runs of test suites, which share the state of the currently
+// running test.
+var runMu sync.Mutex
 
 func isAbortError(x interface{}) bool {
 	_, ok := x.(abortError)
 	return ok
 }
 
-// Run a single test function, returning a slice of failure records.
-func runTestFunction(tf TestFunction) (failures []FailureRecord) {
-	// Set up a clean slate for this test. Make sure to reset it after everything
-	// below is finished, so we don't accidentally use it elsewhere.
-	currentlyRunningTest = newTestInfo()
-	defer func() {
-		currentlyRunningTest = nil
-	}()
-
-	ti := currentlyRunningTest
+// Run a single test function with the supplied full name (e.g.
+// "FooTest.DoesBar"), returning a slice of failure records and the time taken
+// by each phase of the test. The caller is responsible for the timing's name
+// and total fields.
+func runTestFunction(
+	name string,
+	tf TestFunction) (failures []FailureRecord, timing testTiming) {
+	// Set up a clean slate for this test. It is reset below once everything is
+	// finished, so we don't accidentally use it elsewhere.
+	ti := newTestInfo()
+	ti.name = name
+	setCurrentlyRunningTest(ti)
+	defer ti.cancel()
 
 	// Start a trace.
 	var reportOutcome reqtrace.ReportFunc
@@ -67,32 +72,45 @@ func runTestFunction(tf TestFunction) (failures []FailureRecord) {
 	// Run the SetUp function, if any, paying attention to whether it panics.
 	setUpPanicked := false
 	if tf.SetUp != nil {
-		setUpPanicked = runWithProtection(func() { tf.SetUp(ti) })
+		timing.setUp = timeCall(func() {
+			setUpPanicked = runWithProtection(func() { tf.SetUp(ti) })
+		})
 	}
 
 	// Run the test function itself, but only if the SetUp function didn't panic.
-	// (This includes AssertThat errors.)
-	if !setUpPanicked {
-		runWithProtection(tf.Run)
-	}
+	// (This includes AssertThat errors.) Wait for any goroutines the test
+	// started with TestInfo.Go, so that their failures are attributed to this
+	// test.
+	timing.body = timeCall(func() {
+		if !setUpPanicked {
+			runWithProtection(tf.Run)
+		}
+
+		ti.goroutines.Wait()
+	})
 
 	// Run the TearDown function, if any.
 	if tf.TearDown != nil {
-		runWithProtection(tf.TearDown)
+		timing.tearDown = timeCall(func() { runWithProtection(tf.TearDown) })
 	}
 
 	// Tell the mock controller for the tests to report any errors it's sitting
 	// on.
 	ti.MockController.Finish()
 
+	// Detach the test before taking its failures. Goroutines that outlive it
+	// may still hold it, and their failures are reported as late ones.
+	setCurrentlyRunningTest(nil)
+	failures = ti.finish()
+
 	// Report the outcome to reqtrace.
-	if len(ti.failureRecords) == 0 {
+	if len(failures) == 0 {
 		reportOutcome(nil)
 	} else {
-		reportOutcome(fmt.Errorf("%v failure records", len(ti.failureRecords)))
+		reportOutcome(fmt.Errorf("%v failure records", len(failures)))
 	}
 
-	return ti.failureRecords
+	return
 }
 
 // Run everything registered with Register (including via the wrapper
@@ -114,84 +132,226 @@ func runTestFunction(tf TestFunction) (failures []FailureRecord) {
 //       ogletest.RunTests(t)
 //     }
 //
+// Every call to RunTests runs all of the suites again (as does each iteration
+// of `go test -count=N`), so a package should have only one test function that
+// calls it. Use RunSuites to run only some of the suites.
+//
+// Failures reported after a run has finished, e.g. by goroutines that outlive
+// their tests, are printed at the start of the next run. Those reported after
+// the final run are lost.
 func RunTests(t *testing.T) {
-	runTestsOnce.Do(func() { runTestsInternal(t) })
+	runSuites(t, registeredSuites)
+}
+
+// RunSuites runs the registered test suites whose names match the supplied
+// regular expression, in the same way as RunTests. Every call runs the suites,
+// with state independent of other calls, so several test functions can run
+// different subsets of the registered suites:
+//
+//     func TestFoo(t *testing.T) { ogletest.RunSuites(t, "^FooTest$") }
+//     func TestBar(t *testing.T) { ogletest.RunSuites(t, "^Bar") }
+//
+// Calls from concurrently running test functions are serialized. Panics if the
+// filter is not a valid regular expression.
+func RunSuites(t *testing.T, filter string) {
+	re, err := regexp.Compile(filter)
+	if err != nil {
+		panic("RunSuites: invalid filter: " + err.Error())
+	}
+
+	var suites []TestSuite
+	for _, suite := range registeredSuites {
+		if re.MatchString(suite.Name) {
+			suites = append(suites, suite)
+		}
+	}
+
+	runSuites(t, suites)
 }
 
 // Signalling between RunTests and StopRunningTests.
 var gStopRunning uint64
 
+var gStopReasonMu sync.Mutex
+
+// A description of why RunTests was asked to stop, e.g. "user request".
+//
+// GUARDED_BY(gStopReasonMu)
+var gStopReason string
+
 // Request that RunTests stop what it's doing. After the currently running test
-// is finished, including tear-down, the program will exit with an error code.
+// is finished, including tear-down of the test and its suite, RunTests marks
+// the test as failed and returns without running any further tests. Deferred
+// functions, other test functions, and so on run as usual.
 func StopRunningTests() {
+	stopRunning("user request")
+}
+
+// StopRunningTestsWithReason is like StopRunningTests, but the supplied reason
+// is included in the message that RunTests prints when it stops.
+func StopRunningTestsWithReason(reason string) {
+	stopRunning(reason)
+}
+
+// Request that RunTests stop, for the supplied reason. The first reason given
+// is the one reported.
+func stopRunning(reason string) {
+	gStopReasonMu.Lock()
+	defer gStopReasonMu.Unlock()
+
+	if gStopReason == "" {
+		gStopReason = reason
+	}
+
 	atomic.StoreUint64(&gStopRunning, 1)
 }
 
-// runTestsInternal does the real work of RunTests, which simply wraps it in a
-// sync.Once.
-func runTestsInternal(t *testing.T) {
-	// Process each registered suite.
-	for _, suite := range registeredSuites {
+// Clear any request to stop running tests.
+func resetStopRunning() {
+	gStopReasonMu.Lock()
+	defer gStopReasonMu.Unlock()
+
+	gStopReason = ""
+	atomic.StoreUint64(&gStopRunning, 0)
+}
+
+func stopReason() string {
+	gStopReasonMu.Lock()
+	defer gStopReasonMu.Unlock()
+
+	return gStopReason
+}
+
+// RunSuite runs a single test suite, defined as for RegisterTestSuite by a
+// prototype value, in the same way as RunTests. This makes it possible to tie
+// each suite to its own test function, so that `go test -run` can select
+// suites:
+//
+//     type FooTest struct {}
+//     func TestFooTest(t *testing.T) { ogletest.RunSuite(t, &FooTest{}) }
+//
+// The suite need not (and should not) also be registered with
+// RegisterTestSuite. Every call runs the suite, as for RunSuites. Panics if
+// the suite is invalid, as RegisterTestSuite would.
+func RunSuite(t *testing.T, p interface{}) {
+	suite := makeTestSuite("RunSuite", p)
+	checkTestSuite(suite)
+
+	runSuites(t, []TestSuite{suite})
+}
+
+// runSuites does the real work of RunTests and RunSuites, running the supplied
+// suites in order.
+func runSuites(t *testing.T, suites []TestSuite) {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	gUseColor = shouldUseColor()
+
+	// Treat SIGINT and SIGTERM like calls to StopRunningTests. Requests to stop
+	// apply only to this run.
+	stopHandlingSignals := handleSignals()
+	defer stopHandlingSignals()
+	defer resetStopRunning()
+
+	// Failures reported while no test is running should be attributed to this
+	// run from here on, not to the previous one. Print those reported after the
+	// previous run finished, which are labeled as such.
+	printFailureRecords(t, startLateFailures())
+	defer finishLateFailures()
+
+	// Snapshots checked by ExpectSnapshot are written at the end of the run.
+	resetSnapshots()
+
+	// Counts for the summary printed if we stop early.
+	var suitesRun, testsRun, testsFailed int
+
+	// Timings for the slowest tests report.
+	var testTimings []testTiming
+	var suiteTimings []suiteTiming
+
+	// Process each suite.
+	for _, suite := range suites {
 		// Stop now if we've already seen a failure and we've been told to stop
 		// early.
 		if t.Failed() && *fStopEarly {
 			break
 		}
 
+		// Skip suites containing only benchmarks or fuzz targets, which are run
+		// by RunBenchmarks and RunFuzzTarget.
+		if len(suite.TestFunctions) == 0 &&
+			(len(suite.Benchmarks) != 0 || len(suite.FuzzTargets) != 0) {
+			continue
+		}
+
 		// Print a banner.
 		fmt.Printf("[----------] Running tests from %s\n", suite.Name)
+		suitesRun++
+		setLastFinishedTest("")
 
 		// Run the SetUp function, if any.
+		suiteStart := time.Now()
+		sTiming := suiteTiming{name: suite.Name}
 		if suite.SetUp != nil {
-			suite.SetUp()
+			sTiming.setUp = timeCall(suite.SetUp)
+			if msg := formatSlowDuration(sTiming.setUp); msg != "" {
+				fmt.Printf(
+					"[----------] SetUpTestSuite for %s%s\n",
+					suite.Name,
+					msg)
+			}
 		}
 
 		// Run each test function that the user has not told us to skip.
 		stoppedEarly := false
 		for _, tf := range filterTestFunctions(suite) {
 			// Did the user request that we stop running tests? If so, skip the rest
-			// of this suite (and exit after tearing it down).
+			// of this suite (and stop after tearing it down).
 			if atomic.LoadUint64(&gStopRunning) != 0 {
 				stoppedEarly = true
 				break
 			}
 
 			// Print a banner for the start of this test function.
-			fmt.Printf("[ RUN      ] %s.%s\n", suite.Name, tf.Name)
+			fmt.Printf(
+				"%s %s.%s\n",
+				colorize(colorGreen, "[ RUN      ]"),
+				suite.Name,
+				tf.Name)
 
 			// Run the test function.
 			startTime := time.Now()
-			failures := runTestFunction(tf)
-			runDuration := time.Since(startTime)
+			name := fmt.Sprintf("%s.%s", suite.Name, tf.Name)
+			failures, timing := runTestFunction(name, tf)
+			timing.name = name
+			timing.total = time.Since(startTime)
+			testTimings = append(testTimings, timing)
 
 			// Print any failures, and mark the test as having failed if there are any.
-			for _, record := range failures {
-				t.Fail()
-				fmt.Printf(
-					"%s:%d:\n%s\n\n",
-					record.FileName,
-					record.LineNumber,
-					record.Error)
-			}
+			printFailureRecords(t, failures)
 
 			// Print a banner for the end of the test.
-			bannerMessage := "[       OK ]"
+			testsRun++
+			bannerMessage := colorize(colorGreen, "[       OK ]")
 			if len(failures) != 0 {
-				bannerMessage = "[  FAILED  ]"
+				testsFailed++
+				bannerMessage = colorize(colorRed, "[  FAILED  ]")
+			} else {
+				snapshotTestPassed(name)
 			}
 
 			// Print a summary of the time taken, if long enough.
-			var timeMessage string
-			if runDuration >= 25*time.Millisecond {
-				timeMessage = fmt.Sprintf(" (%s)", runDuration.String())
-			}
-
 			fmt.Printf(
-				"%s %s.%s%s\n",
+				"%s %s%s\n",
 				bannerMessage,
-				suite.Name,
-				tf.Name,
-				timeMessage)
+				timing.name,
+				formatSlowDuration(timing.total))
+
+			// Failures reported from now on while no test is running belong to
+			// goroutines that outlived a test. Print any that have already shown up.
+			setLastFinishedTest(timing.name)
+			printLateFailures(t)
 
 			// Stop running tests from this suite if we've been told to stop early
 			// and this test failed.
@@ -202,17 +362,85 @@ func runTestsInternal(t *testing.T) {
 
 		// Run the suite's TearDown function, if any.
 		if suite.TearDown != nil {
-			suite.TearDown()
+			sTiming.tearDown = timeCall(suite.TearDown)
+			if msg := formatSlowDuration(sTiming.tearDown); msg != "" {
+				fmt.Printf(
+					"[----------] TearDownTestSuite for %s%s\n",
+					suite.Name,
+					msg)
+			}
 		}
 
-		// Were we told to exit early?
-		if stoppedEarly {
-			fmt.Println("Exiting early due to user request.")
-			os.Exit(1)
+		sTiming.total = time.Since(suiteStart)
+		suiteTimings = append(suiteTimings, sTiming)
+
+		printLateFailures(t)
+
+		// Were we told to stop early? This may have happened during the last test
+		// in the suite, in which case we have not yet noticed. If so, skip the
+		// remaining suites.
+		if stoppedEarly || atomic.LoadUint64(&gStopRunning) != 0 {
+			t.Fail()
+			fmt.Printf("Stopping early due to %s.\n", stopReason())
+			fmt.Printf(
+				"Ran %s (%d failed) from %s.\n",
+				pluralize(testsRun, "test"),
+				testsFailed,
+				pluralize(suitesRun, "suite"))
+
+			break
 		}
 
 		fmt.Printf("[----------] Finished with tests from %s\n", suite.Name)
 	}
+
+	// Write snapshots and report on them.
+	if err := finishSnapshots(os.Stdout, suites); err != nil {
+		t.Error(err)
+	}
+
+	// Report the slowest tests, if requested.
+	if *fTopSlow > 0 {
+		printSlowest(os.Stdout, testTimings, suiteTimings, *fTopSlow)
+	}
+}
+
+// Return a string like "1 test" or "2 tests".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Print any failures reported while no test was running, marking the test as
+// failed if there are any.
+func printLateFailures(t *testing.T) {
+	printFailureRecords(t, takeLateFailureRecords())
+}
+
+// Print the supplied failure records, marking the test as failed if there are
+// any. Records are printed in the order they were added, each labeled with the
+// scopes it was added in.
+func printFailureRecords(t *testing.T, records []FailureRecord) {
+	for _, record := range records {
+		t.Fail()
+
+		// Follow the location with the scopes the failure happened in, if any.
+		header := fmt.Sprintf("%s:%d:", displayPath(record), record.LineNumber)
+		if len(record.Scopes) != 0 {
+			header += fmt.Sprintf(" [%s]", strings.Join(record.Scopes, ": "))
+		}
+
+		fmt.Printf("%s\n%s\n", header, colorizeError(record.Error))
+
+		if *fSourceSnippets {
+			fmt.Print(sourceSnippet(record.FilePath, record.LineNumber))
+		}
+
+		fmt.Println()
+	}
 }
 
 // Return true iff the supplied program counter appears to lie within panic().
@@ -246,22 +474,29 @@ func findPanic() int {
 	return localSkip - 1
 }
 
-// Attempt to find the file base name and line number for the ultimate source
-// of a panic, on the panicking stack. Return a human-readable sentinel if
-// unsuccessful.
-func findPanicFileLine() (string, int) {
+// Attempt to find the full path, line number, and function name for the
+// ultimate source of a panic, on the panicking stack. Return a human-readable
+// sentinel path if unsuccessful.
+func findPanicFileLine() (file string, line int, funcName string) {
+	file = "(unknown)"
+
 	panicSkip := findPanic()
 	if panicSkip < 0 {
-		return "(unknown)", 0
+		return
 	}
 
 	// Find the trigger of the panic.
-	_, file, line, ok := runtime.Caller(panicSkip + 1)
+	pc, f, l, ok := runtime.Caller(panicSkip + 1)
 	if !ok {
-		return "(unknown)", 0
+		return
+	}
+
+	file, line = f, l
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		funcName = fn.Name()
 	}
 
-	return path.Base(file), line
+	return
 }
 
 // Run the supplied function, catching panics (including AssertThat errors) and
@@ -269,29 +504,25 @@ func findPanicFileLine() (string, int) {
 // the function panicked.
 func runWithProtection(f func()) (panicked bool) {
 	defer func() {
-		// If the test didn't panic, we're done.
+		ti := getCurrentlyRunningTest()
+
+		// If the test didn't panic, we're done. Forget the scopes of any panic
+		// that f recovered from itself.
 		r := recover()
 		if r == nil {
+			ti.clearPanicScopes()
 			return
 		}
 
 		panicked = true
 
-		// We modify the currently running test below.
-		currentlyRunningTest.mu.Lock()
-		defer currentlyRunningTest.mu.Unlock()
-
 		// If the function panicked (and the panic was not due to an AssertThat
-		// failure), add a failure for the panic.
-		if !isAbortError(r) {
-			var panicRecord FailureRecord
-			panicRecord.FileName, panicRecord.LineNumber = findPanicFileLine()
-			panicRecord.Error = fmt.Sprintf(
-				"panic: %v\n\n%s", r, formatPanicStack())
-
-			currentlyRunningTest.failureRecords = append(
-				currentlyRunningTest.failureRecords,
-				panicRecord)
+		// failure), add a failure for the panic. An AssertThat failure was
+		// already recorded with its scopes.
+		if isAbortError(r) {
+			ti.clearPanicScopes()
+		} else {
+			ti.addPanicRecord(newPanicRecord(r))
 		}
 	}()
 
@@ -299,6 +530,26 @@ func runWithProtection(f func()) (panicked bool) {
 	return
 }
 
+// Create a failure record for the supplied value recovered from a panic. Must
+// be called from the deferred function that recovered it.
+func newPanicRecord(r interface{}) (record FailureRecord) {
+	record = FailureRecord{
+		Stack: formatPanicStack(),
+		Time:  time.Now(),
+		Kind:  FailurePanic,
+	}
+
+	record.FilePath, record.LineNumber, record.FunctionName = findPanicFileLine()
+
+	record.FileName = path.Base(record.FilePath)
+	if !path.IsAbs(record.FilePath) {
+		record.FilePath = ""
+	}
+
+	record.Error = fmt.Sprintf("panic: %v\n\n%s", r, record.Stack)
+	return
+}
+
 func formatPanicStack() string {
 	buf := new(bytes.Buffer)
 
@@ -322,8 +573,7 @@ func formatPanicStack() string {
 		}
 
 		// Stop if we've gotten as far as the test runner code.
-		if funcName == "github.com/jacobsa/ogletest.runTestMethod" ||
-			funcName == "github.com/jacobsa/ogletest.runWithProtection" {
+		if isRunnerFunction(funcName) {
 			break
 		}
 
@@ -334,6 +584,30 @@ func formatPanicStack() string {
 	return buf.String()
 }
 
+// Return true iff the named function is part of the test runner, and so should
+// not appear in stacks shown to the user.
+func isRunnerFunction(funcName string) bool {
+	const pkg = "github.com/jacobsa/ogletest."
+	return funcName == pkg+"runTestMethod" ||
+		funcName == pkg+"runBenchmarkMethod" ||
+		funcName == pkg+"runFuzzMethod" ||
+		funcName == pkg+"runWithProtection" ||
+		funcName == pkg+"(*TestInfo).runGoroutine" ||
+		funcName == pkg+"callHooks" ||
+		strings.HasPrefix(funcName, pkg+"makeTestSuiteWithFactory.") ||
+		strings.HasPrefix(funcName, pkg+"runTestFunction.")
+}
+
+// Format the supplied stack frames in the style of a panic stack trace.
+func formatFrames(frames []runtime.Frame) string {
+	buf := new(bytes.Buffer)
+	for _, frame := range frames {
+		fmt.Fprintf(buf, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+
+	return buf.String()
+}
+
 // Filter test functions according to the user-supplied filter flag.
 func filterTestFunctions(suite TestSuite) (out []TestFunction) {
 	re, err := regexp.Compile(*fTestFilter)
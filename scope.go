@@ -0,0 +1,94 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Scope opens a scope for the currently running test, described by a message
+// created by calling fmt.Sprintf using the arguments to this function. Every
+// failure reported while the scope is open, including a panic that escapes it,
+// is tagged with the message, which the runner prints alongside the failure.
+// Scopes may be nested. Scope returns a function that closes the scope, so it
+// is typically used like this:
+//
+//     func checkUser(u *User) {
+//       defer ogletest.Scope("checking user %d", u.ID)()
+//       ExpectEq("jacobsa", u.Name)
+//       ExpectThat(u.Groups, ElementsAre("admin"))
+//     }
+//
+// Scopes belong to the test, not to a goroutine, so failures reported by a
+// goroutine started with TestInfo.Go are tagged with whatever scopes the test
+// has open at the time.
+func Scope(format string, a ...interface{}) (closeScope func()) {
+	ti := getCurrentlyRunningTest()
+	if ti == nil {
+		closeScope = func() {}
+		return
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	depth := len(ti.scopes)
+	ti.scopes = append(ti.scopes, fmt.Sprintf(format, a...))
+	ti.panicScopes = nil
+
+	// Closing the scope also closes any scopes nested within it that were not
+	// closed themselves.
+	closeScope = func() {
+		unwinding := panicking()
+
+		ti.mu.Lock()
+		defer ti.mu.Unlock()
+
+		// A deferred close runs before the runner recovers a panic, so remember
+		// the scopes that the panic escaped for its failure record.
+		switch {
+		case !unwinding:
+			ti.panicScopes = nil
+
+		case ti.panicScopes == nil:
+			ti.panicScopes = append([]string(nil), ti.scopes...)
+		}
+
+		if len(ti.scopes) > depth {
+			ti.scopes = ti.scopes[:depth]
+		}
+	}
+
+	return
+}
+
+// Return true iff the caller is being run by a panic unwinding the stack,
+// i.e. it was deferred by a function that panicked.
+func panicking() bool {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			return true
+		}
+
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestPanicAfterAssertionInScope(t *testing.T) {
+	failures, _ := runTestFunction(
+		"FooTest.DoesBar",
+		TestFunction{
+			Name: "DoesBar",
+			Run: func() {
+				defer Scope("checking user %d", 1)()
+				AssertEq(1, 2)
+			},
+			TearDown: func() { panic("taco") },
+		})
+
+	assertEqInt(t, 2, len(failures))
+	expectEqStr(t, "[checking user 1]", fmt.Sprint(failures[0].Scopes))
+	expectEqStr(t, "[]", fmt.Sprint(failures[1].Scopes))
+}
+
+func TestPanicInScope(t *testing.T) {
+	failures, _ := runTestFunction(
+		"FooTest.DoesBar",
+		TestFunction{
+			Name: "DoesBar",
+			Run: func() {
+				defer Scope("checking user %d", 1)()
+				defer Scope("checking groups")()
+				panic("taco")
+			},
+		})
+
+	assertEqInt(t, 1, len(failures))
+	expectEqStr(
+		t,
+		"[checking user 1 checking groups]",
+		fmt.Sprint(failures[0].Scopes))
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Start treating SIGINT and SIGTERM as requests to stop running tests: the
+// first such signal cancels the context of the currently running test and
+// asks RunTests to stop after that test and its suite have been torn down, as
+// for StopRunningTests. A second signal exits the program immediately.
+//
+// Return a function that restores the default handling of the signals.
+func handleSignals() (stop func()) {
+	c := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-c:
+			fmt.Printf(
+				"\nReceived %v; stopping after the current test. "+
+					"Send it again to exit immediately.\n",
+				sig)
+
+			stopRunning(fmt.Sprintf("signal %v", sig))
+			if ti := getCurrentlyRunningTest(); ti != nil {
+				ti.cancel()
+			}
+
+		case <-done:
+			return
+		}
+
+		select {
+		case sig := <-c:
+			fmt.Printf("Received %v again; exiting immediately.\n", sig)
+			os.Exit(1)
+
+		case <-done:
+		}
+	}()
+
+	stop = func() {
+		signal.Stop(c)
+		close(done)
+	}
+
+	return
+}
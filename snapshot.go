@@ -0,0 +1,451 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var fUpdateSnapshots = flag.Bool(
+	"ogletest.update_snapshots",
+	false,
+	"Overwrite snapshots that don't match the values passed to ExpectSnapshot, "+
+		"and remove unused snapshots, rather than reporting them.")
+
+// The directory, relative to the package being tested, in which snapshot files
+// live. Overridden by tests.
+var snapshotDir = "__snapshots__"
+
+// ExpectSnapshot checks that x matches the snapshot stored for this call, the
+// nth call to ExpectSnapshot or ExpectJSONSnapshot by the current test. The
+// snapshots for each suite are stored in __snapshots__/<Suite>.snap in the
+// package's directory, keyed by test method and n.
+//
+// x is serialized as a pretty-printed Go structure, with one line per struct
+// field, slice element, or map entry; strings are stored as they are. If there
+// is no snapshot for the call yet, ExpectSnapshot records one and passes.
+// Otherwise it reports a diff on a mismatch, or with
+// --ogletest.update_snapshots overwrites the snapshot. errorParts are as for
+// ExpectThat.
+//
+// At the end of the run, ogletest prints a summary of the snapshots written
+// and updated, along with any that are unused: those whose test method no
+// longer exists, or whose test passed without checking them. Unused snapshots
+// are removed when running with --ogletest.update_snapshots.
+//
+// For example:
+//
+//     ExpectSnapshot(parser.Parse("a + b * c"))
+//
+func ExpectSnapshot(x interface{}, errorParts ...interface{}) {
+	expectSnapshot(
+		"ExpectSnapshot",
+		strings.Join(formatLines(reflect.ValueOf(x)), "\n"),
+		1,
+		errorParts)
+}
+
+// ExpectJSONSnapshot is like ExpectSnapshot, but serializes x as indented JSON
+// using encoding/json. It panics if x can't be serialized.
+func ExpectJSONSnapshot(x interface{}, errorParts ...interface{}) {
+	b, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		panic(fmt.Sprintf("ExpectJSONSnapshot: %v", err))
+	}
+
+	expectSnapshot("ExpectJSONSnapshot", string(b), 1, errorParts)
+}
+
+// The generalized form of ExpectSnapshot, checking the supplied serialized
+// value on behalf of the named function. depth is as for expectThat. Returns
+// passed iff the value matched (or the snapshot was written or updated).
+func expectSnapshot(
+	caller string,
+	actual string,
+	depth int,
+	errorParts []interface{}) (passed bool) {
+	ti := getCurrentlyRunningTest()
+	if ti == nil || ti.name == "" {
+		panic(caller + ": no test is running.")
+	}
+
+	ti.mu.Lock()
+	ti.snapshots++
+	n := ti.snapshots
+	ti.mu.Unlock()
+
+	// Test names look like "FooTest.DoesBar", or "FooTest.Base.DoesBar" for
+	// tests inherited from embedded suites.
+	dot := strings.Index(ti.name, ".")
+	suite, method := ti.name[:dot], ti.name[dot+1:]
+	key := fmt.Sprintf("%s %d", method, n)
+
+	msg := checkSnapshot(suite, key, actual)
+	if msg == "" {
+		passed = true
+		return
+	}
+
+	if len(errorParts) != 0 {
+		msg = fmt.Sprintf("%s\n%s", msg, formatErrorParts(caller, errorParts))
+	}
+
+	addGoldenFailure(msg, depth+1)
+	return
+}
+
+// Check the serialized value against the snapshot with the given key in the
+// named suite's file, writing or updating the snapshot as appropriate. Return
+// a failure message, or the empty string if the check passed.
+func checkSnapshot(suite string, key string, actual string) string {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	f, err := snapshots.file(suite)
+	if err != nil {
+		return err.Error()
+	}
+
+	f.used[key] = true
+	expected, ok := f.entries[key]
+	switch {
+	case !ok:
+		f.entries[key] = actual
+		f.dirty = true
+		snapshots.written++
+
+	case expected == actual:
+
+	case *fUpdateSnapshots:
+		f.entries[key] = actual
+		f.dirty = true
+		snapshots.updated++
+
+	default:
+		return mismatchMessage(
+			fmt.Sprintf(
+				"Doesn't match snapshot %q in %s; rerun with "+
+					"--ogletest.update_snapshots to update it.",
+				key,
+				f.path),
+			expected,
+			actual)
+	}
+
+	return ""
+}
+
+////////////////////////////////////////////////////////////////////////
+// Snapshot state
+////////////////////////////////////////////////////////////////////////
+
+// The snapshots for a single suite, stored in a single file.
+type snapshotFile struct {
+	path string
+
+	// The serialized values, keyed by test method and call number, e.g.
+	// "DoesBar 1".
+	entries map[string]string
+
+	// The keys checked during this run.
+	used map[string]bool
+
+	// Whether entries have changed since the file was read.
+	dirty bool
+}
+
+// The snapshot state for a single call to runSuites.
+type snapshotState struct {
+	// Files read so far, by suite name.
+	files map[string]*snapshotFile
+
+	// The names of the tests that have run and passed, e.g. "FooTest.DoesBar".
+	passed map[string]bool
+
+	// Counts for the summary.
+	written int
+	updated int
+}
+
+var snapshotsMu sync.Mutex
+
+// GUARDED_BY(snapshotsMu)
+var snapshots = newSnapshotState()
+
+func newSnapshotState() *snapshotState {
+	return &snapshotState{
+		files:  make(map[string]*snapshotFile),
+		passed: make(map[string]bool),
+	}
+}
+
+// Return the snapshots for the named suite, reading them if necessary.
+func (s *snapshotState) file(suite string) (f *snapshotFile, err error) {
+	if f = s.files[suite]; f != nil {
+		return
+	}
+
+	path := filepath.Join(snapshotDir, suite+".snap")
+	entries, err := readSnapshotFile(path)
+	if err != nil {
+		return
+	}
+
+	f = &snapshotFile{
+		path:    path,
+		entries: entries,
+		used:    make(map[string]bool),
+	}
+
+	s.files[suite] = f
+	return
+}
+
+// Forget the snapshot state of any previous run.
+func resetSnapshots() {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	snapshots = newSnapshotState()
+}
+
+// Record that the named test ran and passed, so that any of its snapshots that
+// it didn't check are unused.
+func snapshotTestPassed(name string) {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	snapshots.passed[name] = true
+}
+
+// Find the unused snapshots of the supplied suites, removing them if
+// requested, write any files that have changed, and print a summary to w if
+// anything is worth reporting.
+func finishSnapshots(w io.Writer, suites []TestSuite) (err error) {
+	snapshotsMu.Lock()
+	defer snapshotsMu.Unlock()
+
+	var unused []string
+	for _, suite := range suites {
+		tests := make(map[string]bool)
+		for _, tf := range suite.TestFunctions {
+			tests[tf.Name] = true
+		}
+
+		var f *snapshotFile
+		f, err = snapshots.file(suite.Name)
+		if err != nil {
+			return
+		}
+
+		for _, key := range sortedSnapshotKeys(f.entries) {
+			method := key[:strings.LastIndex(key, " ")]
+			if f.used[key] ||
+				(tests[method] && !snapshots.passed[suite.Name+"."+method]) {
+				continue
+			}
+
+			unused = append(unused, suite.Name+"."+key)
+			if *fUpdateSnapshots {
+				delete(f.entries, key)
+				f.dirty = true
+			}
+		}
+
+		if f.dirty {
+			if err = writeSnapshotFile(f.path, f.entries); err != nil {
+				return
+			}
+
+			f.dirty = false
+		}
+	}
+
+	if snapshots.written == 0 && snapshots.updated == 0 && len(unused) == 0 {
+		return
+	}
+
+	fmt.Fprintf(
+		w,
+		"[----------] Snapshots: %d written, %d updated, %d unused\n",
+		snapshots.written,
+		snapshots.updated,
+		len(unused))
+
+	for _, name := range unused {
+		if *fUpdateSnapshots {
+			fmt.Fprintf(w, "[----------] Removed unused snapshot %s\n", name)
+		} else {
+			fmt.Fprintf(
+				w,
+				"[----------] Unused snapshot %s "+
+					"(rerun with --ogletest.update_snapshots to remove it)\n",
+				name)
+		}
+	}
+
+	return
+}
+
+////////////////////////////////////////////////////////////////////////
+// Snapshot files
+////////////////////////////////////////////////////////////////////////
+
+// Snapshot files consist of entries like the following, with each line of the
+// serialized value prefixed by "| ", or "|" for empty lines. Blank lines
+// between entries and comment lines starting with "//" are ignored.
+//
+//     === DoesBar 1
+//     | FooTest{
+//     |   Name: "taco",
+//     | }
+//
+
+const snapshotFileHeader = "// Snapshots checked by ExpectSnapshot. Rerun the " +
+	"tests with --ogletest.update_snapshots\n// to update them.\n"
+
+// Return the keys of the supplied entries, sorted by test method and then call
+// number.
+func sortedSnapshotKeys(entries map[string]string) (keys []string) {
+	for k := range entries {
+		keys = append(keys, k)
+	}
+
+	split := func(key string) (method string, n int) {
+		i := strings.LastIndex(key, " ")
+		n, _ = strconv.Atoi(key[i+1:])
+		method = key[:i]
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		mi, ni := split(keys[i])
+		mj, nj := split(keys[j])
+		if mi != mj {
+			return mi < mj
+		}
+
+		return ni < nj
+	})
+
+	return
+}
+
+// Read the snapshot file at the supplied path, returning no entries if it
+// doesn't exist.
+func readSnapshotFile(path string) (entries map[string]string, err error) {
+	entries = make(map[string]string)
+
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		err = nil
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("Reading snapshots: %v", err)
+		return
+	}
+
+	var key string
+	var lines []string
+	flush := func() {
+		if key != "" {
+			entries[key] = strings.Join(lines, "\n")
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(nil, len(contents)+1)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "//"):
+
+		case strings.HasPrefix(line, "=== "):
+			flush()
+			key = line[len("=== "):]
+			lines = nil
+
+			if !strings.Contains(key, " ") {
+				err = fmt.Errorf("%s:%d: invalid snapshot name %q", path, lineNum, key)
+				return
+			}
+
+		case strings.HasPrefix(line, "|") && key != "":
+			line = strings.TrimPrefix(line[1:], " ")
+			lines = append(lines, line)
+
+		default:
+			err = fmt.Errorf("%s:%d: unexpected line %q", path, lineNum, line)
+			return
+		}
+	}
+
+	flush()
+	return
+}
+
+// Write the supplied entries to the snapshot file at the given path, or remove
+// the file if there are none.
+func writeSnapshotFile(path string, entries map[string]string) (err error) {
+	if len(entries) == 0 {
+		err = os.Remove(path)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(snapshotFileHeader)
+	for _, key := range sortedSnapshotKeys(entries) {
+		fmt.Fprintf(buf, "\n=== %s\n", key)
+		for _, line := range strings.Split(entries[key], "\n") {
+			if line == "" {
+				buf.WriteString("|\n")
+			} else {
+				fmt.Fprintf(buf, "| %s\n", line)
+			}
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		err = fmt.Errorf("Writing snapshots: %v", err)
+		return
+	}
+
+	if err = ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		err = fmt.Errorf("Writing snapshots: %v", err)
+		return
+	}
+
+	return
+}
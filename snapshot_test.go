@@ -0,0 +1,267 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type snapshotTestStruct struct {
+	Name  string
+	Tags  []string
+	Count int
+}
+
+// A suite with the supplied test method names, for finishSnapshots.
+func snapshotTestSuite(methods ...string) TestSuite {
+	suite := TestSuite{Name: "FooTest"}
+	for _, m := range methods {
+		suite.TestFunctions = append(suite.TestFunctions, TestFunction{Name: m})
+	}
+
+	return suite
+}
+
+// Point snapshotDir at a fresh temporary directory, returning a function that
+// restores it and removes the directory.
+func useTempSnapshotDir(t *testing.T) (dir string, restore func()) {
+	dir, err := ioutil.TempDir("", "snapshot_test")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+
+	old := snapshotDir
+	snapshotDir = dir
+	resetSnapshots()
+
+	restore = func() {
+		snapshotDir = old
+		resetSnapshots()
+		os.RemoveAll(dir)
+	}
+
+	return
+}
+
+// Run f as the named test of FooTest, returning its failure records. The test
+// counts as passed if it has none.
+func runSnapshotTest(name string, f func()) []FailureRecord {
+	setUpCurrentTest()
+	currentlyRunningTest.name = "FooTest." + name
+	f()
+
+	records := currentlyRunningTest.failureRecords
+	if len(records) == 0 {
+		snapshotTestPassed(currentlyRunningTest.name)
+	}
+
+	return records
+}
+
+// Finish a run of a suite with the supplied test methods, returning the
+// summary.
+func finishSnapshotRun(t *testing.T, update bool, methods ...string) string {
+	defer func(old bool) { *fUpdateSnapshots = old }(*fUpdateSnapshots)
+	*fUpdateSnapshots = update
+
+	buf := new(bytes.Buffer)
+	if err := finishSnapshots(buf, []TestSuite{snapshotTestSuite(methods...)}); err != nil {
+		t.Fatalf("finishSnapshots: %v", err)
+	}
+
+	resetSnapshots()
+	return buf.String()
+}
+
+func readSnapshots(t *testing.T, dir string) string {
+	contents, err := ioutil.ReadFile(filepath.Join(dir, "FooTest.snap"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	return string(contents)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestSnapshotsWrittenOnFirstRun(t *testing.T) {
+	dir, restore := useTempSnapshotDir(t)
+	defer restore()
+
+	records := runSnapshotTest("DoesBar", func() {
+		ExpectSnapshot(snapshotTestStruct{Name: "taco", Tags: []string{"a", ""}})
+		ExpectSnapshot("foo\n\n  bar")
+		ExpectJSONSnapshot(map[string]int{"b": 2, "a": 1})
+	})
+
+	assertEqInt(t, 0, len(records))
+	expectEqStr(
+		t,
+		"[----------] Snapshots: 3 written, 0 updated, 0 unused\n",
+		finishSnapshotRun(t, false, "DoesBar"))
+
+	expectEqStr(
+		t,
+		snapshotFileHeader+`
+=== DoesBar 1
+| ogletest.snapshotTestStruct{
+|   Name: "taco",
+|   Tags: []string{
+|     "a",
+|     "",
+|   },
+|   Count: 0,
+| }
+
+=== DoesBar 2
+| foo
+|
+|   bar
+
+=== DoesBar 3
+| {
+|   "a": 1,
+|   "b": 2
+| }
+`,
+		readSnapshots(t, dir))
+
+	// The same values match on the next run, which says nothing.
+	records = runSnapshotTest("DoesBar", func() {
+		ExpectSnapshot(snapshotTestStruct{Name: "taco", Tags: []string{"a", ""}})
+		ExpectSnapshot("foo\n\n  bar")
+		ExpectJSONSnapshot(map[string]int{"a": 1, "b": 2})
+	})
+
+	assertEqInt(t, 0, len(records))
+	expectEqStr(t, "", finishSnapshotRun(t, false, "DoesBar"))
+}
+
+func TestSnapshotMismatch(t *testing.T) {
+	dir, restore := useTempSnapshotDir(t)
+	defer restore()
+
+	runSnapshotTest("DoesBar", func() { ExpectSnapshot([]int{1, 2, 3}) })
+	finishSnapshotRun(t, false, "DoesBar")
+	before := readSnapshots(t, dir)
+
+	records := runSnapshotTest("DoesBar", func() {
+		ExpectSnapshot([]int{1, 4, 3}, "for user %d", 17)
+	})
+
+	assertEqInt(t, 1, len(records))
+	expectEqStr(t, "snapshot_test.go", records[0].FileName)
+
+	err := records[0].Error
+	for _, s := range []string{
+		`Doesn't match snapshot "DoesBar 1"`,
+		"--ogletest.update_snapshots",
+		"-   2,",
+		"+   4,",
+		"for user 17",
+	} {
+		if !strings.Contains(err, s) {
+			t.Errorf("Expected %q in error: %s", s, err)
+		}
+	}
+
+	// The snapshot is left alone.
+	expectEqStr(t, "", finishSnapshotRun(t, false, "DoesBar"))
+	expectEqStr(t, before, readSnapshots(t, dir))
+}
+
+func TestSnapshotUpdate(t *testing.T) {
+	dir, restore := useTempSnapshotDir(t)
+	defer restore()
+
+	runSnapshotTest("DoesBar", func() { ExpectSnapshot("taco") })
+	finishSnapshotRun(t, false, "DoesBar")
+
+	defer func(old bool) { *fUpdateSnapshots = old }(*fUpdateSnapshots)
+	*fUpdateSnapshots = true
+
+	records := runSnapshotTest("DoesBar", func() { ExpectSnapshot("burrito") })
+	assertEqInt(t, 0, len(records))
+	expectEqStr(
+		t,
+		"[----------] Snapshots: 0 written, 1 updated, 0 unused\n",
+		finishSnapshotRun(t, true, "DoesBar"))
+
+	expectEqStr(
+		t,
+		snapshotFileHeader+"\n=== DoesBar 1\n| burrito\n",
+		readSnapshots(t, dir))
+}
+
+func TestUnusedSnapshots(t *testing.T) {
+	dir, restore := useTempSnapshotDir(t)
+	defer restore()
+
+	// Write snapshots for three tests.
+	runSnapshotTest("DoesBar", func() {
+		ExpectSnapshot(1)
+		ExpectSnapshot(2)
+	})
+
+	runSnapshotTest("DoesBaz", func() { ExpectSnapshot(3) })
+	runSnapshotTest("DoesQux", func() { ExpectSnapshot(4) })
+	finishSnapshotRun(t, false, "DoesBar", "DoesBaz", "DoesQux")
+
+	// Now DoesBar checks only one snapshot, DoesBaz fails without checking its
+	// snapshot, and DoesQux no longer exists.
+	runSnapshotTest("DoesBar", func() { ExpectSnapshot(1) })
+	runSnapshotTest("DoesBaz", func() { AddFailure("taco") })
+
+	expectEqStr(
+		t,
+		"[----------] Snapshots: 0 written, 0 updated, 2 unused\n"+
+			"[----------] Unused snapshot FooTest.DoesBar 2 "+
+			"(rerun with --ogletest.update_snapshots to remove it)\n"+
+			"[----------] Unused snapshot FooTest.DoesQux 1 "+
+			"(rerun with --ogletest.update_snapshots to remove it)\n",
+		finishSnapshotRun(t, false, "DoesBar", "DoesBaz"))
+
+	// With the update flag, they are removed.
+	runSnapshotTest("DoesBar", func() { ExpectSnapshot(1) })
+	expectEqStr(
+		t,
+		"[----------] Snapshots: 0 written, 0 updated, 2 unused\n"+
+			"[----------] Removed unused snapshot FooTest.DoesBar 2\n"+
+			"[----------] Removed unused snapshot FooTest.DoesQux 1\n",
+		finishSnapshotRun(t, true, "DoesBar", "DoesBaz"))
+
+	expectEqStr(
+		t,
+		snapshotFileHeader+"\n=== DoesBar 1\n| 1\n\n=== DoesBaz 1\n| 3\n",
+		readSnapshots(t, dir))
+}
+
+func TestSnapshotOutsideTest(t *testing.T) {
+	setUpCurrentTest()
+	msg := panicMessage(func() { ExpectSnapshot(17) })
+	expectEqStr(t, "ExpectSnapshot: no test is running.", msg)
+}
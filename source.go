@@ -0,0 +1,158 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var fSourceSnippets = flag.Bool(
+	"ogletest.source_snippets",
+	true,
+	"If true, print the source code around the location of each failure.")
+
+// Return the path of the file in which the supplied failure happened, for
+// printing. Files within the module containing the working directory (or
+// within the working directory, outside of a module) are given relative to
+// the working directory, which for 'go test' is the package being tested, so
+// that failures in the package show just the file name. Other files are given
+// in full. Records without a full path fall back to the file name.
+func displayPath(r FailureRecord) string {
+	if r.FilePath == "" {
+		return r.FileName
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return r.FilePath
+	}
+
+	if !isWithin(moduleRoot(wd), r.FilePath) {
+		return r.FilePath
+	}
+
+	rel, err := filepath.Rel(wd, r.FilePath)
+	if err != nil {
+		return r.FilePath
+	}
+
+	return rel
+}
+
+// Return the root of the module containing dir, i.e. the nearest enclosing
+// directory with a go.mod file, or dir itself if there is none.
+func moduleRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+
+		d = parent
+	}
+}
+
+// Return true iff path lies within dir.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil &&
+		rel != ".." &&
+		!strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// The number of lines of source to print on either side of a failing line.
+const snippetContext = 2
+
+var sourceFilesMu sync.Mutex
+
+// The lines of source files read so far, keyed by path. A nil entry means
+// the file couldn't be read.
+//
+// GUARDED_BY(sourceFilesMu)
+var sourceFiles = make(map[string][]string)
+
+func readSourceLines(path string) []string {
+	sourceFilesMu.Lock()
+	defer sourceFilesMu.Unlock()
+
+	lines, ok := sourceFiles[path]
+	if !ok {
+		if contents, err := ioutil.ReadFile(path); err == nil {
+			trimmed := strings.TrimSuffix(string(contents), "\n")
+			lines = strings.Split(trimmed, "\n")
+		}
+
+		sourceFiles[path] = lines
+	}
+
+	return lines
+}
+
+// Return the lines of source around the given line of the file at the given
+// path, with the line itself marked, or the empty string if the file can't be
+// read. Each line of the result ends in a newline.
+func sourceSnippet(path string, line int) string {
+	if path == "" {
+		return ""
+	}
+
+	lines := readSourceLines(path)
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first := line - snippetContext
+	if first < 1 {
+		first = 1
+	}
+
+	last := line + snippetContext
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	// Line numbers are right-aligned to the width of the largest.
+	width := len(fmt.Sprint(last))
+
+	buf := new(bytes.Buffer)
+	for i := first; i <= last; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+
+		l := fmt.Sprintf("%s %*d | %s", marker, width, i, lines[i-1])
+		l = strings.TrimRight(l, " ")
+		if i == line {
+			l = colorize(colorBold, l)
+		}
+
+		fmt.Fprintf(buf, "%s\n", l)
+	}
+
+	return buf.String()
+}
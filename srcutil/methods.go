@@ -22,44 +22,163 @@ import (
 	"sort"
 )
 
-func getLine(m reflect.Method) int {
-	pc := m.Func.Pointer()
+// The file name the runtime reports for wrapper functions generated by the
+// compiler, such as those for promoted methods.
+const autogeneratedFile = "<autogenerated>"
 
+// A position in the source code.
+type position struct {
+	file string
+	line int
+}
+
+// Order positions by file then line, with unknown positions (those in
+// autogeneratedFile) last.
+func (p position) less(q position) bool {
+	pUnknown := p.file == autogeneratedFile
+	qUnknown := q.file == autogeneratedFile
+	if pUnknown || qUnknown {
+		return !pUnknown && qUnknown
+	}
+
+	if p.file != q.file {
+		return p.file < q.file
+	}
+
+	return p.line < q.line
+}
+
+// Return the position of the function with the supplied entry point, which may
+// be in autogeneratedFile.
+func funcPosition(pc uintptr) position {
 	f := runtime.FuncForPC(pc)
 	if f == nil {
-		panic(fmt.Sprintf("Couldn't get runtime func for method (pc=%d): %v", pc, m))
+		panic(fmt.Sprintf("Couldn't get runtime func for pc %d", pc))
+	}
+
+	file, line := f.FileLine(pc)
+	return position{file, line}
+}
+
+// Return the position at which the method with the supplied name in the
+// method set of t is declared, along with the depth of embedding at which it
+// was found. Compiler-generated wrappers are resolved to the method they wrap:
+// either a method with a value receiver seen through a pointer, or a method
+// promoted from an embedded field, in which case the shallowest one is used,
+// as with Go's selector rules.
+//
+// ok is false if the method isn't in the method set of t. If it is but its
+// declaration can't be found (e.g. because it is promoted from an embedded
+// interface), the position is in autogeneratedFile.
+func methodPosition(
+	t reflect.Type,
+	name string,
+	visited map[reflect.Type]bool) (pos position, depth int, ok bool) {
+	m, ok := t.MethodByName(name)
+	if !ok {
+		return
+	}
+
+	pos = position{autogeneratedFile, 0}
+	if t.Kind() == reflect.Interface {
+		return
 	}
 
-	_, line := f.FileLine(pc)
-	return line
+	pos = funcPosition(m.Func.Pointer())
+	if pos.file != autogeneratedFile {
+		return
+	}
+
+	// If the method is in the method set of the pointed-to type, the wrapper is
+	// for that method.
+	base := t
+	if t.Kind() == reflect.Ptr {
+		base = t.Elem()
+		if p, d, ok := methodPosition(base, name, visited); ok {
+			return p, d, true
+		}
+	}
+
+	if base.Kind() != reflect.Struct || visited[base] {
+		return
+	}
+
+	visited[base] = true
+	defer delete(visited, base)
+
+	// Otherwise the method must be promoted from an embedded field. The method
+	// set of a pointer includes that of the type it points to, so look at
+	// pointers to each embedded field.
+	found := false
+	for i := 0; i < base.NumField(); i++ {
+		f := base.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() != reflect.Ptr && ft.Kind() != reflect.Interface {
+			ft = reflect.PtrTo(ft)
+		}
+
+		p, d, ok := methodPosition(ft, name, visited)
+		if !ok || (found && d+1 >= depth) {
+			continue
+		}
+
+		found = true
+		pos = p
+		depth = d + 1
+	}
+
+	return
 }
 
-type sortableMethodSet []reflect.Method
+type sortableMethodSet struct {
+	methods   []reflect.Method
+	positions []position
+}
 
 func (s sortableMethodSet) Len() int {
-	return len(s)
+	return len(s.methods)
 }
 
 func (s sortableMethodSet) Less(i, j int) bool {
-	return getLine(s[i]) < getLine(s[j])
+	return s.positions[i].less(s.positions[j])
 }
 
 func (s sortableMethodSet) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+	s.methods[i], s.methods[j] = s.methods[j], s.methods[i]
+	s.positions[i], s.positions[j] = s.positions[j], s.positions[i]
 }
 
-// Given a type t, return all of the methods of t sorted such that source file
-// order is preserved. Order across files is undefined. Order within lines is
-// undefined.
+// GetMethodsInSourceOrder returns the methods in the method set of t, ordered
+// by the position of their declarations: by file path, then by line within the
+// file. Methods promoted from embedded fields are ordered by where they are
+// declared on the embedded type, and methods whose declarations can't be found
+// (such as those promoted from embedded interfaces) come last, in the order
+// given by reflect.
 func GetMethodsInSourceOrder(t reflect.Type) []reflect.Method {
 	// Build the list of methods.
 	methods := sortableMethodSet{}
 	for i := 0; i < t.NumMethod(); i++ {
-		methods = append(methods, t.Method(i))
+		m := t.Method(i)
+		pos, _, _ := methodPosition(t, m.Name, make(map[reflect.Type]bool))
+		methods.methods = append(methods.methods, m)
+		methods.positions = append(methods.positions, pos)
 	}
 
 	// Sort it.
-	sort.Sort(methods)
+	sort.Stable(methods)
+
+	return methods.methods
+}
 
-	return methods
+// MethodDepth returns the depth of embedding at which the method with the
+// supplied name in the method set of t is declared: zero if it is declared on
+// t itself (or the type that t points to), one if it is promoted from a field
+// embedded in t, and so on. ok is false if there is no such method.
+func MethodDepth(t reflect.Type, name string) (depth int, ok bool) {
+	_, depth, ok = methodPosition(t, name, make(map[reflect.Type]bool))
+	return
 }
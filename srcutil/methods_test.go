@@ -46,6 +46,56 @@ func (x MultipleMethodsType) Foo() {}
 func (x MultipleMethodsType) Bar() {}
 func (x MultipleMethodsType) Baz() {}
 
+type MixedReceiversType int
+
+func (x *MixedReceiversType) Foo() {}
+func (x MixedReceiversType) Bar()  {}
+func (x *MixedReceiversType) Baz() {}
+
+type PromotedMethodsType struct {
+	EmbeddedType
+	*PointerEmbeddedType
+}
+
+func (x *PromotedMethodsType) Foo() {}
+
+type EmbeddedType struct{}
+
+func (x *EmbeddedType) Bar() {}
+func (x EmbeddedType) Baz()  {}
+
+type PointerEmbeddedType struct{}
+
+func (x *PointerEmbeddedType) Qux() {}
+
+func (x *PromotedMethodsType) Quux() {}
+
+type DeeplyPromotedMethodsType struct {
+	PromotedMethodsType
+}
+
+func (x *DeeplyPromotedMethodsType) Corge() {}
+
+type ShadowingType struct {
+	EmbeddedType
+}
+
+func (x *ShadowingType) Foo() {}
+func (x *ShadowingType) Bar() {}
+
+type InterfaceEmbeddingType struct {
+	fmt.Stringer
+}
+
+func (x *InterfaceEmbeddingType) Foo() {}
+
+// More methods for this type are in methods_two_test.go, which sorts after
+// this file.
+type MultipleFilesType int
+
+func (x MultipleFilesType) Foo() {}
+func (x MultipleFilesType) Bar() {}
+
 type methodNameMatcher struct {
 	expected string
 }
@@ -105,3 +155,130 @@ func (t *MethodsTest) MultipleMethods() {
 	ExpectEq("Bar", methods[1].Name)
 	ExpectEq("Baz", methods[2].Name)
 }
+
+func (t *MethodsTest) MixedReceivers() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(new(MixedReceiversType)))
+
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Foo"),
+			NameIs("Bar"),
+			NameIs("Baz"),
+		))
+}
+
+func (t *MethodsTest) PromotedMethods() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(new(PromotedMethodsType)))
+
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Foo"),
+			NameIs("Bar"),
+			NameIs("Baz"),
+			NameIs("Qux"),
+			NameIs("Quux"),
+		))
+}
+
+func (t *MethodsTest) PromotedMethodsOfValueType() {
+	// Only the value methods of EmbeddedType, and the methods of the embedded
+	// pointer, are in the method set.
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(PromotedMethodsType{}))
+
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Baz"),
+			NameIs("Qux"),
+		))
+}
+
+func (t *MethodsTest) DeeplyPromotedMethods() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(new(DeeplyPromotedMethodsType)))
+
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Foo"),
+			NameIs("Bar"),
+			NameIs("Baz"),
+			NameIs("Qux"),
+			NameIs("Quux"),
+			NameIs("Corge"),
+		))
+}
+
+func (t *MethodsTest) ShadowedMethods() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(new(ShadowingType)))
+
+	// Baz is promoted from EmbeddedType, but Bar is shadowed by the declaration
+	// on ShadowingType.
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Baz"),
+			NameIs("Foo"),
+			NameIs("Bar"),
+		))
+}
+
+func (t *MethodsTest) EmbeddedInterface() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(new(InterfaceEmbeddingType)))
+
+	// Methods promoted from interfaces have no declaration, so come last.
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Foo"),
+			NameIs("String"),
+		))
+}
+
+func (t *MethodsTest) MultipleFiles() {
+	methods := srcutil.GetMethodsInSourceOrder(
+		reflect.TypeOf(MultipleFilesType(17)))
+
+	// Files are ordered by path, so methods_test.go comes before
+	// methods_two_test.go even though the latter's methods have smaller line
+	// numbers.
+	ExpectThat(
+		methods,
+		ElementsAre(
+			NameIs("Foo"),
+			NameIs("Bar"),
+			NameIs("Baz"),
+			NameIs("Qux"),
+		))
+}
+
+func (t *MethodsTest) MethodDepths() {
+	var depth int
+	var ok bool
+
+	depth, ok = srcutil.MethodDepth(reflect.TypeOf(new(PromotedMethodsType)), "Foo")
+	ExpectTrue(ok)
+	ExpectEq(0, depth)
+
+	depth, ok = srcutil.MethodDepth(reflect.TypeOf(new(PromotedMethodsType)), "Baz")
+	ExpectTrue(ok)
+	ExpectEq(1, depth)
+
+	depth, ok = srcutil.MethodDepth(reflect.TypeOf(new(DeeplyPromotedMethodsType)), "Qux")
+	ExpectTrue(ok)
+	ExpectEq(2, depth)
+
+	depth, ok = srcutil.MethodDepth(reflect.TypeOf(new(ShadowingType)), "Bar")
+	ExpectTrue(ok)
+	ExpectEq(0, depth)
+
+	_, ok = srcutil.MethodDepth(reflect.TypeOf(new(ShadowingType)), "Qux")
+	ExpectFalse(ok)
+}
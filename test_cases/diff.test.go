@@ -0,0 +1,87 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestDiff(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type DiffTest struct {
+}
+
+func init() { RegisterTestSuite(&DiffTest{}) }
+
+type address struct {
+	Street string
+	City   string
+}
+
+type person struct {
+	Name    string
+	Age     int
+	Address address
+	Tags    []string
+	Scores  map[string]int
+}
+
+func makePerson() person {
+	return person{
+		Name:    "Aaron",
+		Age:     17,
+		Address: address{"Main St", "Sydney"},
+		Tags:    []string{"a", "b", "c", "d", "e", "f", "g", "h"},
+		Scores:  map[string]int{"math": 90, "art": 80},
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *DiffTest) DeepEqualsStructs() {
+	expected := makePerson()
+	actual := makePerson()
+	actual.Address.City = "Melbourne"
+	actual.Tags[6] = "G"
+	actual.Scores["art"] = 81
+
+	ExpectThat(actual, DeepEquals(expected))
+}
+
+func (t *DiffTest) DeepEqualsSlices() {
+	ExpectThat(
+		[]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		DeepEquals([]int{1, 2, 3, 4, 5, 6, 7, 8, 10}))
+}
+
+func (t *DiffTest) MultiLineStrings() {
+	ExpectEq("foo\nbar\nbaz", "foo\nbaz\nqux")
+	ExpectThat("foo\nbar", Equals("foo\nbaz"))
+}
+
+func (t *DiffTest) SingleLineValuesHaveNoDiff() {
+	ExpectEq("taco", "burrito")
+	ExpectThat(17, DeepEquals(19))
+}
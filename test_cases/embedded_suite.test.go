@@ -0,0 +1,127 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestEmbeddedSuite(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// CommonSuite
+////////////////////////////////////////////////////////////////////////
+
+// A base suite with fixtures and tests shared by the suites that embed it.
+type CommonSuite struct {
+	ready bool
+}
+
+func (t *CommonSuite) SetUpTestSuite() {
+	fmt.Println("CommonSuite.SetUpTestSuite")
+}
+
+func (t *CommonSuite) SetUp(ti *TestInfo) {
+	fmt.Println("CommonSuite.SetUp")
+	t.ready = true
+}
+
+func (t *CommonSuite) TearDown() {
+	fmt.Println("CommonSuite.TearDown")
+}
+
+func (t *CommonSuite) IsReady() {
+	ExpectTrue(t.ready)
+}
+
+func (t *CommonSuite) SlowInheritedTest() {
+	fmt.Println("SlowInheritedTest")
+}
+
+////////////////////////////////////////////////////////////////////////
+// MiddleSuite
+////////////////////////////////////////////////////////////////////////
+
+type MiddleSuite struct {
+	CommonSuite `ogletest:"chain"`
+}
+
+func (t *MiddleSuite) SetUp(ti *TestInfo) {
+	fmt.Println("MiddleSuite.SetUp")
+}
+
+func (t *MiddleSuite) MiddleTest() {
+	ExpectTrue(t.ready)
+}
+
+////////////////////////////////////////////////////////////////////////
+// EmbeddingTest
+////////////////////////////////////////////////////////////////////////
+
+type EmbeddingTest struct {
+	MiddleSuite `ogletest:"chain"`
+}
+
+func init() { RegisterTestSuite(&EmbeddingTest{}) }
+
+func (t *EmbeddingTest) NonTestMethods() []string {
+	return []string{"SlowInheritedTest"}
+}
+
+func (t *EmbeddingTest) SetUp(ti *TestInfo) {
+	fmt.Println("EmbeddingTest.SetUp")
+}
+
+func (t *EmbeddingTest) TearDown() {
+	fmt.Println("EmbeddingTest.TearDown")
+}
+
+func (t *EmbeddingTest) TearDownTestSuite() {
+	fmt.Println("EmbeddingTest.TearDownTestSuite")
+}
+
+func (t *EmbeddingTest) OwnTest() {
+	ExpectThat(t.ready, Equals(false))
+}
+
+////////////////////////////////////////////////////////////////////////
+// ExplicitBaseTest
+////////////////////////////////////////////////////////////////////////
+
+// A suite that embeds CommonSuite without chaining, and so calls its SetUp
+// explicitly. CommonSuite's other hooks are promoted as usual.
+type ExplicitBaseTest struct {
+	CommonSuite `ogletest:"nochain"`
+}
+
+func init() { RegisterTestSuite(&ExplicitBaseTest{}) }
+
+func (t *ExplicitBaseTest) NonTestMethods() []string {
+	return []string{"SlowInheritedTest"}
+}
+
+func (t *ExplicitBaseTest) SetUp(ti *TestInfo) {
+	t.CommonSuite.SetUp(ti)
+	fmt.Println("ExplicitBaseTest.SetUp")
+}
+
+func (t *ExplicitBaseTest) ExplicitTest() {
+	ExpectTrue(t.ready)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestFuzzSuite(t *testing.T) { RunTests(t) }
+
+func FuzzSuiteReverse(f *testing.F) { RunFuzzTarget(f, "FuzzSuiteTest.Reverse") }
+func FuzzSuiteRepeat(f *testing.F)  { RunFuzzTarget(f, "FuzzSuiteTest.Repeat") }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+func reverseString(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r)
+}
+
+////////////////////////////////////////////////////////////////////////
+// FuzzSuiteTest
+////////////////////////////////////////////////////////////////////////
+
+type FuzzSuiteTest struct {
+	reverse func(string) string
+}
+
+func init() { RegisterTestSuite(&FuzzSuiteTest{}) }
+
+func (t *FuzzSuiteTest) SetUpTestSuite() {
+	fmt.Println("SetUpTestSuite")
+}
+
+func (t *FuzzSuiteTest) TearDownTestSuite() {
+	fmt.Println("TearDownTestSuite")
+}
+
+func (t *FuzzSuiteTest) SetUp(ti *TestInfo) {
+	t.reverse = reverseString
+}
+
+func (t *FuzzSuiteTest) FuzzSeeds() map[string][][]interface{} {
+	return map[string][][]interface{}{
+		"FuzzReverse": {{"taco"}, {"añb"}},
+		"FuzzRepeat":  {{"ab", 3}, {"x", -1}},
+	}
+}
+
+func (t *FuzzSuiteTest) ReverseTwice() {
+	ExpectEq("taco", t.reverse(t.reverse("taco")))
+}
+
+func (t *FuzzSuiteTest) FuzzReverse(s string) {
+	ExpectEq(s, t.reverse(t.reverse(s)))
+	ExpectEq(len(s), len(t.reverse(s)))
+}
+
+func (t *FuzzSuiteTest) FuzzRepeat(s string, n int) {
+	AssertThat(n, GreaterOrEqual(0))
+	ExpectEq(len(s)*n, len(strings.Repeat(s, n)))
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestGoroutine(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+type GoroutineTest struct {
+	ti *TestInfo
+}
+
+func init() { RegisterTestSuite(&GoroutineTest{}) }
+
+// Closed by TearDownTestSuite to let the goroutine started by
+// OutlivesTest report its failure.
+var release = make(chan struct{})
+var released = make(chan struct{})
+
+func (t *GoroutineTest) SetUp(ti *TestInfo) {
+	t.ti = ti
+}
+
+func (t *GoroutineTest) TearDownTestSuite() {
+	close(release)
+	<-released
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *GoroutineTest) PassingMethod() {
+}
+
+func (t *GoroutineTest) ExpectFromGo() {
+	t.ti.Go(func() {
+		ExpectThat(17, Equals(19))
+	})
+}
+
+func (t *GoroutineTest) AssertFromGo() {
+	t.ti.Go(func() {
+		AssertThat(17, Equals(19))
+		fmt.Println("Shouldn't get here.")
+	})
+
+	fmt.Println("Test method still running.")
+}
+
+func (t *GoroutineTest) PanicFromGo() {
+	t.ti.Go(func() {
+		panic("taco")
+	})
+}
+
+func (t *GoroutineTest) AssertFromPlainGoroutine() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		AssertThat(17, Equals(19))
+		fmt.Println("Shouldn't get here.")
+	}()
+
+	<-done
+}
+
+func (t *GoroutineTest) OutlivesTest() {
+	go func() {
+		defer close(released)
+		<-release
+		AddFailure("taco")
+	}()
+}
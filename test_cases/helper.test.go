@@ -0,0 +1,100 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+	"github.com/jacobsa/ogletest/test_cases/mock_image"
+)
+
+func TestHelper(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type HelperTest struct {
+	image mock_image.MockImage
+}
+
+func init() { RegisterTestSuite(&HelperTest{}) }
+
+func (t *HelperTest) SetUp(ti *TestInfo) {
+	t.image = mock_image.NewMockImage(ti.MockController, "some mock image")
+}
+
+func checkPositive(x int) {
+	Helper()
+	ExpectGt(x, 0)
+}
+
+func assertPositive(x int) {
+	Helper()
+	AssertThat(x, GreaterThan(0))
+}
+
+func checkBothPositive(x, y int) {
+	Helper()
+	checkPositive(x)
+	checkPositive(y)
+}
+
+func failTwice() {
+	Helper()
+	AddFailure("taco")
+	AddFailure("burrito")
+}
+
+func expectAt(image mock_image.MockImage) {
+	Helper()
+	ExpectCall(image, "At")(Any(), Any())
+}
+
+func notAHelper() {
+	ExpectEq(17, 19)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *HelperTest) ExpectThat() {
+	checkPositive(-1)
+}
+
+func (t *HelperTest) AssertThat() {
+	assertPositive(-1)
+	AddFailure("Shouldn't get here.")
+}
+
+func (t *HelperTest) NestedHelpers() {
+	checkBothPositive(1, -1)
+}
+
+func (t *HelperTest) AddFailure() {
+	failTwice()
+}
+
+func (t *HelperTest) ExpectCall() {
+	expectAt(t.image)
+}
+
+func (t *HelperTest) UnmarkedFunction() {
+	notAHelper()
+}
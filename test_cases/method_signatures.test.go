@@ -0,0 +1,74 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+	"golang.org/x/net/context"
+)
+
+func TestMethodSignatures(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// MethodSignaturesTest
+////////////////////////////////////////////////////////////////////////
+
+type MethodSignaturesTest struct {
+	ti *TestInfo
+}
+
+func init() { RegisterTestSuite(&MethodSignaturesTest{}) }
+
+func (t *MethodSignaturesTest) SetUp(ti *TestInfo) {
+	t.ti = ti
+}
+
+func (t *MethodSignaturesTest) NoArgs() {
+	fmt.Println("NoArgs")
+}
+
+func (t *MethodSignaturesTest) TakesTestInfo(ti *TestInfo) {
+	ExpectEq(t.ti, ti)
+	ExpectNe(nil, ti.MockController)
+}
+
+func (t *MethodSignaturesTest) TakesContext(ctx context.Context) {
+	ExpectEq(t.ti.Ctx, ctx)
+	ExpectThat(ctx.Err(), Equals(nil))
+}
+
+func (t *MethodSignaturesTest) ReturnsNilError() error {
+	return nil
+}
+
+func (t *MethodSignaturesTest) ReturnsError() error {
+	return errors.New("taco")
+}
+
+func (t *MethodSignaturesTest) TakesTestInfoAndReturnsError(
+	ti *TestInfo) error {
+	return fmt.Errorf("burrito: %v", ti != nil)
+}
+
+func (t *MethodSignaturesTest) TakesContextAndReturnsError(
+	ctx context.Context) error {
+	return ctx.Err()
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"sort"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestProperty(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// A buggy de-duplication function, which only removes adjacent duplicates.
+func dedupe(xs []int) (out []int) {
+	for i, x := range xs {
+		if i == 0 || x != xs[i-1] {
+			out = append(out, x)
+		}
+	}
+
+	return
+}
+
+type point struct {
+	X int
+	Y int
+}
+
+////////////////////////////////////////////////////////////////////////
+// PropertyTest
+////////////////////////////////////////////////////////////////////////
+
+type PropertyTest struct {
+}
+
+func init() { RegisterTestSuite(&PropertyTest{}) }
+
+func (t *PropertyTest) SortingIsIdempotent() {
+	ExpectForAll(Arbitrary(), func(xs []int) {
+		sort.Ints(xs)
+		ys := append([]int{}, xs...)
+		sort.Ints(ys)
+		ExpectThat(ys, DeepEquals(xs))
+	})
+}
+
+func (t *PropertyTest) DedupeRemovesAllDuplicates() {
+	ExpectForAll(Arbitrary(), func(xs []int) {
+		seen := make(map[int]bool)
+		for _, x := range dedupe(xs) {
+			ExpectFalse(seen[x], "duplicate: %d", x)
+			seen[x] = true
+		}
+	})
+}
+
+func (t *PropertyTest) PointsAreNearOrigin() {
+	ExpectForAll(
+		Arbitrary(),
+		func(p point) {
+			AssertLt(p.X*p.X+p.Y*p.Y, 25)
+		},
+		"points should be within %d of the origin",
+		5)
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+// Registered suites are run by RunTests, but not by RunSuite. The integration
+// test selects only the RunSuite functions with -run.
+func TestRegistered(t *testing.T) { RunTests(t) }
+func TestFooSuite(t *testing.T)   { RunSuite(t, &FooSuite{}) }
+func TestBarSuite(t *testing.T)   { RunSuite(t, &BarSuite{}) }
+
+////////////////////////////////////////////////////////////////////////
+// RegisteredSuite
+////////////////////////////////////////////////////////////////////////
+
+type RegisteredSuite struct {
+}
+
+func init() { RegisterTestSuite(&RegisteredSuite{}) }
+
+func (t *RegisteredSuite) PassingMethod() {
+}
+
+////////////////////////////////////////////////////////////////////////
+// FooSuite
+////////////////////////////////////////////////////////////////////////
+
+type FooSuite struct {
+}
+
+func (t *FooSuite) PassingMethod() {
+}
+
+////////////////////////////////////////////////////////////////////////
+// BarSuite
+////////////////////////////////////////////////////////////////////////
+
+type BarSuite struct {
+}
+
+func (t *BarSuite) FailingMethod() {
+	ExpectThat(17, Equals(19))
+}
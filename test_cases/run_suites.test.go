@@ -0,0 +1,66 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+// Each of these runs a different subset of the suites, and the last runs all
+// of them again.
+func TestFoo(t *testing.T)  { RunSuites(t, "^FooTest$") }
+func TestBar(t *testing.T)  { RunSuites(t, "^BarTest$") }
+func TestBoth(t *testing.T) { RunSuites(t, "") }
+
+////////////////////////////////////////////////////////////////////////
+// FooTest
+////////////////////////////////////////////////////////////////////////
+
+type FooTest struct {
+}
+
+func init() { RegisterTestSuite(&FooTest{}) }
+
+func (t *FooTest) SetUpTestSuite() {
+	fmt.Println("FooTest.SetUpTestSuite running.")
+}
+
+func (t *FooTest) PassingMethod() {
+}
+
+////////////////////////////////////////////////////////////////////////
+// BarTest
+////////////////////////////////////////////////////////////////////////
+
+type BarTest struct {
+}
+
+func init() { RegisterTestSuite(&BarTest{}) }
+
+func (t *BarTest) FailingMethod() {
+	ExpectThat(17, Equals(19))
+}
+
+func (t *BarTest) StoppingMethod() {
+	StopRunningTests()
+}
+
+func (t *BarTest) SkippedMethod() {
+}
@@ -30,8 +30,8 @@ type RunTwiceTest struct {
 
 func init() { RegisterTestSuite(&RunTwiceTest{}) }
 
-// Set up two helpers that call RunTests. The test should still only be run
-// once.
+// Set up two helpers that call RunTests. Each should run the tests, as when
+// running with -count=2.
 func TestOgletest(t *testing.T)  { RunTests(t) }
 func TestOgletest2(t *testing.T) { RunTests(t) }
 
@@ -0,0 +1,79 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestScope(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type ScopeTest struct {
+}
+
+func init() { RegisterTestSuite(&ScopeTest{}) }
+
+type user struct {
+	id     int
+	name   string
+	groups []string
+}
+
+func checkUser(u user) {
+	defer Scope("checking user %d", u.id)()
+	ExpectEq("jacobsa", u.name)
+
+	func() {
+		defer Scope("checking groups")()
+		ExpectThat(u.groups, ElementsAre("admin"))
+	}()
+
+	ExpectGt(u.id, 0)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *ScopeTest) PassingScopes() {
+	checkUser(user{17, "jacobsa", []string{"admin"}})
+}
+
+func (t *ScopeTest) NestedScopes() {
+	checkUser(user{-1, "taco", []string{"burrito"}})
+}
+
+func (t *ScopeTest) KeepsFailuresInOrder() {
+	ExpectEq(17, 19)
+	checkUser(user{17, "taco", nil})
+	ExpectEq(23, 29)
+	checkUser(user{19, "enchilada", nil})
+}
+
+func (t *ScopeTest) PanicInScope() {
+	defer Scope("checking user %d", 23)()
+	func() {
+		defer Scope("checking groups")()
+		panic("taco")
+	}()
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestSharedFixture(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// A stand-in for an expensive fixture such as a database.
+type fakeDatabase struct {
+	rows map[string]int
+}
+
+func startFakeDatabase() *fakeDatabase {
+	fmt.Println("startFakeDatabase")
+	return &fakeDatabase{rows: make(map[string]int)}
+}
+
+////////////////////////////////////////////////////////////////////////
+// SharedFixtureTest
+////////////////////////////////////////////////////////////////////////
+
+type SharedFixtureTest struct {
+	DB    *fakeDatabase `ogletest:"shared"`
+	Label string        `ogletest:"shared"`
+
+	// Not shared; zero at the start of every test.
+	counter int
+}
+
+func init() { RegisterTestSuite(&SharedFixtureTest{}) }
+
+func (t *SharedFixtureTest) SetUpTestSuite() {
+	t.DB = startFakeDatabase()
+	t.Label = "suite"
+	t.counter = 17
+}
+
+func (t *SharedFixtureTest) TearDownTestSuite() {
+	fmt.Println("TearDownTestSuite:", t.Label, t.DB.rows["taco"], t.counter)
+}
+
+func (t *SharedFixtureTest) SetUp(ti *TestInfo) {
+	fmt.Println("SetUp:", t.Label, t.DB != nil, t.counter)
+}
+
+func (t *SharedFixtureTest) WritesToFixture() {
+	t.DB.rows["taco"]++
+	t.Label = "changed"
+	t.counter++
+}
+
+func (t *SharedFixtureTest) SeesEarlierWrites() {
+	ExpectThat(t.DB.rows["taco"], Equals(1))
+	ExpectEq("suite", t.Label)
+	ExpectEq(0, t.counter)
+}
+
+func (t *SharedFixtureTest) UnsharedFieldsAreNotCopied() {
+	ExpectThat(t.counter, Equals(17))
+}
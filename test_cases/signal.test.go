@@ -0,0 +1,75 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestSignal(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+type SignalTest struct {
+	ti *TestInfo
+}
+
+func init() { RegisterTestSuite(&SignalTest{}) }
+
+func (t *SignalTest) SetUp(ti *TestInfo) {
+	t.ti = ti
+}
+
+func (t *SignalTest) TearDown() {
+	fmt.Println("TearDown running.")
+}
+
+func (t *SignalTest) TearDownTestSuite() {
+	fmt.Println("TearDownTestSuite running.")
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *SignalTest) First() {
+}
+
+func (t *SignalTest) Second() {
+	p, err := os.FindProcess(os.Getpid())
+	AssertEq(nil, err)
+
+	fmt.Println("About to send SIGINT.")
+	AssertEq(nil, p.Signal(os.Interrupt))
+
+	select {
+	case <-t.ti.Ctx.Done():
+		fmt.Println("Context cancelled.")
+
+	case <-time.After(10 * time.Second):
+		AddFailure("Context not cancelled.")
+	}
+}
+
+func (t *SignalTest) Third() {
+}
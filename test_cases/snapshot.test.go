@@ -0,0 +1,56 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"testing"
+
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestSnapshot(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type order struct {
+	Customer string
+	Items    []string
+	Total    int
+}
+
+////////////////////////////////////////////////////////////////////////
+// SnapshotTest
+////////////////////////////////////////////////////////////////////////
+
+type SnapshotTest struct {
+}
+
+func init() { RegisterTestSuite(&SnapshotTest{}) }
+
+func (t *SnapshotTest) RecordsStructs() {
+	ExpectSnapshot(order{"taco", []string{"burrito", "enchilada"}, 17})
+}
+
+func (t *SnapshotTest) RecordsSeveralValues() {
+	ExpectSnapshot("foo\nbar")
+	ExpectJSONSnapshot(order{Customer: "taco"})
+}
+
+func (t *SnapshotTest) MissingGoldenFile() {
+	ExpectMatchesGolden("report.txt", "foo\nbar\n")
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"path"
+	"runtime"
+	"testing"
+
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestSnippets(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+type SnippetsTest struct {
+}
+
+func init() { RegisterTestSuite(&SnippetsTest{}) }
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *SnippetsTest) ExpectationFailure() {
+	x := 17
+
+	ExpectEq(19, x)
+	ExpectLt(x, 0)
+}
+
+func (t *SnippetsTest) AddFailure() {
+	AddFailure("taco")
+}
+
+func (t *SnippetsTest) AddFailureRecordWithoutPath() {
+	AddFailureRecord(FailureRecord{
+		FileName:   "foo.go",
+		LineNumber: 17,
+		Error:      "burrito",
+	})
+}
+
+func (t *SnippetsTest) LastLineOfFile() {
+	AddFailureRecord(FailureRecord{
+		FileName:   "snippets_test.go",
+		FilePath:   snippetsPath(),
+		LineNumber: 91,
+		Error:      "enchilada",
+	})
+}
+
+func (t *SnippetsTest) FileInSubdirectory() {
+	AddFailureRecord(FailureRecord{
+		FileName:   "bar.go",
+		FilePath:   path.Join(path.Dir(snippetsPath()), "foo", "bar.go"),
+		LineNumber: 19,
+		Error:      "queso",
+	})
+}
+
+func (t *SnippetsTest) FileOutsideModule() {
+	AddFailureRecord(FailureRecord{
+		FileName:   "bar.go",
+		FilePath:   "/does/not/exist/bar.go",
+		LineNumber: 23,
+		Error:      "nachos",
+	})
+}
+
+// Return the path of this file.
+func snippetsPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file
+}
@@ -24,6 +24,10 @@ import (
 
 func TestStop(t *testing.T) { RunTests(t) }
 
+func TestAfterStop(t *testing.T) {
+	fmt.Println("Other test functions still run.")
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Boilerplate
 ////////////////////////////////////////////////////////////////////////
@@ -52,10 +56,26 @@ func (t *StopTest) First() {
 }
 
 func (t *StopTest) Second() {
-	fmt.Println("About to call StopRunningTests.")
-	StopRunningTests()
-	fmt.Println("Called StopRunningTests.")
+	fmt.Println("About to call StopRunningTestsWithReason.")
+	StopRunningTestsWithReason("taco")
+	fmt.Println("Called StopRunningTestsWithReason.")
 }
 
 func (t *StopTest) Third() {
 }
+
+////////////////////////////////////////////////////////////////////////
+// Later suite
+////////////////////////////////////////////////////////////////////////
+
+type StopLaterTest struct {
+}
+
+func init() { RegisterTestSuite(&StopLaterTest{}) }
+
+func (t *StopLaterTest) SetUpTestSuite() {
+	fmt.Println("Shouldn't get here.")
+}
+
+func (t *StopLaterTest) First() {
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oglematchers_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+func TestSuiteFunc(t *testing.T) { RunTests(t) }
+
+////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////
+
+// A fake dependency injected by the factory below.
+type fakeGreeter struct {
+	greeting string
+}
+
+func (g *fakeGreeter) Greet(name string) string {
+	return fmt.Sprintf("%s, %s", g.greeting, name)
+}
+
+////////////////////////////////////////////////////////////////////////
+// SuiteFuncTest
+////////////////////////////////////////////////////////////////////////
+
+type SuiteFuncTest struct {
+	greeter *fakeGreeter
+	calls   int
+}
+
+func init() {
+	RegisterTestSuiteFunc(func() *SuiteFuncTest {
+		fmt.Println("factory")
+		return &SuiteFuncTest{greeter: &fakeGreeter{greeting: "Hello"}}
+	})
+}
+
+func (t *SuiteFuncTest) SetUpTestSuite() {
+	fmt.Println("SetUpTestSuite:", t.greeter.greeting)
+}
+
+func (t *SuiteFuncTest) TearDownTestSuite() {
+	fmt.Println("TearDownTestSuite:", t.greeter.greeting)
+}
+
+func (t *SuiteFuncTest) SetUp(ti *TestInfo) {
+	t.calls++
+	fmt.Println("SetUp:", t.greeter.greeting)
+}
+
+func (t *SuiteFuncTest) TearDown() {
+	fmt.Println("TearDown:", t.calls)
+}
+
+func (t *SuiteFuncTest) UsesInjectedDependency() {
+	ExpectEq("Hello, taco", t.greeter.Greet("taco"))
+}
+
+func (t *SuiteFuncTest) GetsFreshInstance() {
+	ExpectThat(t.calls, Equals(1))
+	t.greeter.greeting = "Goodbye"
+	ExpectEq("Goodbye, burrito", t.greeter.Greet("burrito"))
+}
+
+func (t *SuiteFuncTest) FailureWithInjectedDependency() {
+	ExpectEq("Hello, taco", t.greeter.Greet("burrito"))
+}
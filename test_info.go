@@ -16,7 +16,10 @@
 package ogletest
 
 import (
+	"fmt"
+	"path"
 	"sync"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -36,9 +39,17 @@ type TestInfo struct {
 
 	// A context that can be used by tests for long-running operations. In
 	// particular, this enables conveniently tracing the execution of a test
-	// function with reqtrace.
+	// function with reqtrace. The context is cancelled if the test run is
+	// interrupted by a signal.
 	Ctx context.Context
 
+	// Cancels Ctx.
+	cancel context.CancelFunc
+
+	// The name of the test, e.g. "FooTest.DoesBar", or the empty string if this
+	// isn't a test function (e.g. a benchmark).
+	name string
+
 	// A mutex protecting shared state.
 	mu sync.RWMutex
 
@@ -46,20 +57,198 @@ type TestInfo struct {
 	//
 	// GUARDED_BY(mu)
 	failureRecords []FailureRecord
+
+	// Set once the runner has taken the failure records. Records added after
+	// that, e.g. by goroutines that outlive the test, are reported as late
+	// failures.
+	//
+	// GUARDED_BY(mu)
+	finished bool
+
+	// The context messages of the scopes (see Scope) currently open, outermost
+	// first.
+	//
+	// GUARDED_BY(mu)
+	scopes []string
+
+	// The scopes that were open when a panic started unwinding through them, or
+	// nil if none has since the last scope was opened or closed normally.
+	//
+	// GUARDED_BY(mu)
+	panicScopes []string
+
+	// The number of snapshots the test has checked with ExpectSnapshot.
+	//
+	// GUARDED_BY(mu)
+	snapshots int
+
+	// Goroutines started with Go that have not yet returned.
+	goroutines sync.WaitGroup
 }
 
 // currentlyRunningTest is the state for the currently running test, if any.
+//
+// GUARDED_BY(currentlyRunningTestMu)
 var currentlyRunningTest *TestInfo
+var currentlyRunningTestMu sync.Mutex
+
+// getCurrentlyRunningTest returns the state for the currently running test,
+// or nil if none. It is safe to call from any goroutine.
+func getCurrentlyRunningTest() *TestInfo {
+	currentlyRunningTestMu.Lock()
+	defer currentlyRunningTestMu.Unlock()
+
+	return currentlyRunningTest
+}
+
+// setCurrentlyRunningTest updates the state returned by
+// getCurrentlyRunningTest.
+func setCurrentlyRunningTest(ti *TestInfo) {
+	currentlyRunningTestMu.Lock()
+	defer currentlyRunningTestMu.Unlock()
+
+	currentlyRunningTest = ti
+}
 
 // newTestInfo creates a valid but empty TestInfo struct.
 func newTestInfo() (info *TestInfo) {
 	info = &TestInfo{}
 	info.MockController = oglemock.NewController(&testInfoErrorReporter{info})
-	info.Ctx = context.Background()
+	info.Ctx, info.cancel = context.WithCancel(context.Background())
+
+	return
+}
+
+// Add a failure record to the test, tagging it with the scopes currently open
+// if it doesn't already have any.
+func (ti *TestInfo) addFailureRecord(r FailureRecord) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.addFailureRecordLocked(r)
+}
+
+// Like addFailureRecord, but for a record created by newPanicRecord: the
+// record is tagged with the scopes that the panic escaped, if any.
+func (ti *TestInfo) addPanicRecord(r FailureRecord) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if ti.panicScopes != nil {
+		r.Scopes = ti.panicScopes
+		ti.panicScopes = nil
+	}
+
+	ti.addFailureRecordLocked(r)
+}
+
+// Forget the scopes recorded for a panic that didn't become a panic record.
+func (ti *TestInfo) clearPanicScopes() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.panicScopes = nil
+}
+
+// Append the record, tagging it with the scopes currently open if it doesn't
+// already have any.
+//
+// LOCKS_REQUIRED(ti.mu)
+func (ti *TestInfo) addFailureRecordLocked(r FailureRecord) {
+	if r.Scopes == nil && len(ti.scopes) != 0 {
+		r.Scopes = append([]string(nil), ti.scopes...)
+	}
+
+	if ti.finished {
+		addFailureAfterTest(ti.name, r)
+		return
+	}
+
+	ti.failureRecords = append(ti.failureRecords, r)
+}
+
+// Mark the test as finished, returning the failure records it has produced.
+// The caller should first make sure that the test is no longer the currently
+// running test.
+func (ti *TestInfo) finish() (failures []FailureRecord) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.finished = true
+	failures = ti.failureRecords
+	ti.failureRecords = nil
+	return
+}
+
+// Run f, returning rather than recording the failures that it adds to the test.
+// A panic from f, including one from a failed assertion, stops f; unless it is
+// from an assertion, it is returned as a failure too.
+func (ti *TestInfo) captureFailures(f func()) (failures []FailureRecord) {
+	ti.mu.Lock()
+	n := len(ti.failureRecords)
+	ti.mu.Unlock()
 
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil || isAbortError(r) {
+				return
+			}
+
+			record := FailureRecord{
+				Error: fmt.Sprintf("panic: %v", r),
+				Time:  time.Now(),
+				Kind:  FailurePanic,
+			}
+
+			record.FilePath, record.LineNumber, record.FunctionName =
+				findPanicFileLine()
+
+			record.FileName = path.Base(record.FilePath)
+			ti.addFailureRecord(record)
+		}()
+
+		f()
+	}()
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	failures = append(failures, ti.failureRecords[n:]...)
+	ti.failureRecords = ti.failureRecords[:n]
 	return
 }
 
+// Go runs f in a new goroutine on behalf of the test. The test is not
+// considered finished until f returns: the runner waits for it after the test
+// method and before TearDown, so any failures that f reports with ExpectThat,
+// AddFailure, and friends are attributed to this test rather than to a later
+// one or reported after it finished. A panic in f is recorded as a failure of
+// this test.
+//
+// As with any goroutine other than the one running the test, AssertThat and
+// AbortTest called within f stop only f, not the test method.
+func (ti *TestInfo) Go(f func()) {
+	ti.goroutines.Add(1)
+	go ti.runGoroutine(f)
+}
+
+// Run f on behalf of the test, as for Go.
+func (ti *TestInfo) runGoroutine(f func()) {
+	defer ti.goroutines.Done()
+	defer func() {
+		r := recover()
+		if r == nil || isAbortError(r) {
+			ti.clearPanicScopes()
+			return
+		}
+
+		ti.addPanicRecord(newPanicRecord(r))
+	}()
+
+	f()
+}
+
 // testInfoErrorReporter is an oglemock.ErrorReporter that writes failure
 // records into a test info struct.
 type testInfoErrorReporter struct {
@@ -70,16 +259,20 @@ func (r *testInfoErrorReporter) ReportError(
 	fileName string,
 	lineNumber int,
 	err error) {
-	r.testInfo.mu.Lock()
-	defer r.testInfo.mu.Unlock()
-
 	record := FailureRecord{
 		FileName:   fileName,
 		LineNumber: lineNumber,
 		Error:      err.Error(),
+		Time:       time.Now(),
+		Kind:       FailureMock,
+	}
+
+	// oglemock passes along the full path given to ExpectCall, when it has one.
+	if path.IsAbs(fileName) {
+		record.FilePath = fileName
 	}
 
-	r.testInfo.failureRecords = append(r.testInfo.failureRecords, record)
+	r.testInfo.addFailureRecord(record)
 }
 
 func (r *testInfoErrorReporter) ReportFatalError(
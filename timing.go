@@ -0,0 +1,121 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+var fSlowThreshold = flag.Duration(
+	"ogletest.slow_threshold",
+	25*time.Millisecond,
+	"Print the time taken by tests and suite set-up and tear-down that take "+
+		"at least this long.")
+
+var fTopSlow = flag.Int(
+	"ogletest.top_slow",
+	0,
+	"If positive, print this many of the slowest tests and suites at the end "+
+		"of the run.")
+
+// The time taken by a single test function, broken down by phase. The total
+// includes time not accounted for by the phases, such as checking mock
+// expectations.
+type testTiming struct {
+	name     string
+	setUp    time.Duration
+	body     time.Duration
+	tearDown time.Duration
+	total    time.Duration
+}
+
+// The time taken by a test suite. The total includes the time taken by its
+// test functions.
+type suiteTiming struct {
+	name     string
+	setUp    time.Duration
+	tearDown time.Duration
+	total    time.Duration
+}
+
+// Return a parenthesized description of d, preceded by a space, if d is at
+// least the slow test threshold. Otherwise return the empty string.
+func formatSlowDuration(d time.Duration) string {
+	if d < *fSlowThreshold {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s)", d.String())
+}
+
+// Run f, returning the time it took.
+func timeCall(f func()) time.Duration {
+	start := time.Now()
+	f()
+	return time.Since(start)
+}
+
+// Write reports of the n slowest tests and suites to w.
+func printSlowest(
+	w io.Writer,
+	tests []testTiming,
+	suites []suiteTiming,
+	n int) {
+	tests = append([]testTiming(nil), tests...)
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].total > tests[j].total
+	})
+
+	if len(tests) > n {
+		tests = tests[:n]
+	}
+
+	suites = append([]suiteTiming(nil), suites...)
+	sort.SliceStable(suites, func(i, j int) bool {
+		return suites[i].total > suites[j].total
+	})
+
+	if len(suites) > n {
+		suites = suites[:n]
+	}
+
+	fmt.Fprintf(w, "[----------] %d slowest tests:\n", len(tests))
+	for _, t := range tests {
+		fmt.Fprintf(
+			w,
+			"[----------]   %s: %v (SetUp %v, test %v, TearDown %v)\n",
+			t.name,
+			t.total,
+			t.setUp,
+			t.body,
+			t.tearDown)
+	}
+
+	fmt.Fprintf(w, "[----------] %d slowest suites:\n", len(suites))
+	for _, s := range suites {
+		fmt.Fprintf(
+			w,
+			"[----------]   %s: %v (SetUpTestSuite %v, TearDownTestSuite %v)\n",
+			s.name,
+			s.total,
+			s.setUp,
+			s.tearDown)
+	}
+}
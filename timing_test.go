@@ -0,0 +1,61 @@
+// Copyright 2015 Aaron Jacobs. All Rights Reserved.
+// Author: aaronjjacobs@gmail.com (Aaron Jacobs)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogletest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func TestFormatSlowDuration(t *testing.T) {
+	defer func(old time.Duration) { *fSlowThreshold = old }(*fSlowThreshold)
+	*fSlowThreshold = 100 * time.Millisecond
+
+	expectEqStr(t, "", formatSlowDuration(99*time.Millisecond))
+	expectEqStr(t, " (100ms)", formatSlowDuration(100*time.Millisecond))
+	expectEqStr(t, " (1.5s)", formatSlowDuration(1500*time.Millisecond))
+}
+
+func TestPrintSlowest(t *testing.T) {
+	tests := []testTiming{
+		{"FooTest.A", 1 * time.Millisecond, 2 * time.Millisecond, 0, 3 * time.Millisecond},
+		{"FooTest.B", 0, 30 * time.Millisecond, 0, 31 * time.Millisecond},
+		{"BarTest.C", 0, 10 * time.Millisecond, 5 * time.Millisecond, 15 * time.Millisecond},
+	}
+
+	suites := []suiteTiming{
+		{"FooTest", 0, 0, 40 * time.Millisecond},
+		{"BarTest", 50 * time.Millisecond, 1 * time.Millisecond, 70 * time.Millisecond},
+	}
+
+	buf := new(bytes.Buffer)
+	printSlowest(buf, tests, suites, 2)
+
+	expectEqStr(
+		t,
+		"[----------] 2 slowest tests:\n"+
+			"[----------]   FooTest.B: 31ms (SetUp 0s, test 30ms, TearDown 0s)\n"+
+			"[----------]   BarTest.C: 15ms (SetUp 0s, test 10ms, TearDown 5ms)\n"+
+			"[----------] 2 slowest suites:\n"+
+			"[----------]   BarTest: 70ms (SetUpTestSuite 50ms, TearDownTestSuite 1ms)\n"+
+			"[----------]   FooTest: 40ms (SetUpTestSuite 0s, TearDownTestSuite 0s)\n",
+		buf.String())
+}